@@ -19,8 +19,11 @@ package lib
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/worker"
+	"net/http"
 	"reflect"
 	"strconv"
 	"sync"
@@ -124,3 +127,258 @@ func TestTermAggregationLimit(t *testing.T) {
 	t.Run("check limit 100", check(100, 100))
 	t.Run("check limit 150", check(150, 150))
 }
+
+func TestAggregations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	t.Run("create d1", saveTestDevice(w, "devices", "d1", map[string]interface{}{"name": "d1", "device_type_id": "dt1", "owner_id": "o1"}))
+	t.Run("create d2", saveTestDevice(w, "devices", "d2", map[string]interface{}{"name": "d2", "device_type_id": "dt1", "owner_id": "o2"}))
+	t.Run("create d3", saveTestDevice(w, "devices", "d3", map[string]interface{}{"name": "d3", "device_type_id": "dt1", "owner_id": "o2"}))
+	t.Run("create d4", saveTestDevice(w, "devices", "d4", map[string]interface{}{"name": "d4", "device_type_id": "dt2", "owner_id": "o1"}))
+	t.Run("create p1", saveTestProcessModel(w, "p1", map[string]interface{}{"name": "p1", "date": "2026-01-05T10:00:00Z"}))
+	t.Run("create p2", saveTestProcessModel(w, "p2", map[string]interface{}{"name": "p2", "date": "2026-01-06T10:00:00Z"}))
+	t.Run("create p3", saveTestProcessModel(w, "p3", map[string]interface{}{"name": "p3", "date": "2026-01-20T10:00:00Z"}))
+
+	time.Sleep(2 * time.Second)
+
+	query := func(token string, resource string, aggregations map[string]model.Aggregation) (result map[string]model.AggregationValue, err error) {
+		temp, code, err := q.Query(token, model.QueryMessage{Resource: resource, Aggregations: aggregations})
+		if err != nil {
+			return result, fmt.Errorf("%v: %w", code, err)
+		}
+		return temp.(map[string]model.AggregationValue), nil
+	}
+
+	t.Run("nested terms", func(t *testing.T) {
+		result, err := query(testtoken, "devices", map[string]model.Aggregation{
+			"device_types": {
+				Type:  model.AggregationTypeTerms,
+				Field: "device_type_id",
+				Aggregations: map[string]model.Aggregation{
+					"owners": {Type: model.AggregationTypeTerms, Field: "features.owner_id"},
+				},
+			},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected := map[string]model.AggregationValue{
+			"device_types": {Buckets: []model.AggregationBucket{
+				{Key: "dt1", Count: 3, Aggregations: map[string]model.AggregationValue{
+					"owners": {Buckets: []model.AggregationBucket{{Key: "o2", Count: 2}, {Key: "o1", Count: 1}}},
+				}},
+				{Key: "dt2", Count: 1, Aggregations: map[string]model.AggregationValue{
+					"owners": {Buckets: []model.AggregationBucket{{Key: "o1", Count: 1}}},
+				}},
+			}},
+		}
+		if !reflect.DeepEqual(result, expected) {
+			a, _ := json.Marshal(result)
+			e, _ := json.Marshal(expected)
+			t.Error(string(a), string(e))
+		}
+	})
+
+	t.Run("cardinality", func(t *testing.T) {
+		result, err := query(testtoken, "devices", map[string]model.Aggregation{
+			"distinct_device_types": {Type: model.AggregationTypeCardinality, Field: "device_type_id"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value := result["distinct_device_types"].Cardinality; value == nil || *value != 2 {
+			t.Error(result)
+		}
+	})
+
+	t.Run("date histogram and stats", func(t *testing.T) {
+		result, err := query(testtoken, "processmodel", map[string]model.Aggregation{
+			"per_week": {Type: model.AggregationTypeDateHistogram, Field: "date", Interval: "week"},
+			"dates":    {Type: model.AggregationTypeStats, Field: "date"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		counts := []int64{}
+		for _, bucket := range result["per_week"].Buckets {
+			counts = append(counts, bucket.Count)
+		}
+		if !reflect.DeepEqual(counts, []int64{2, 0, 1}) {
+			t.Error(counts, result)
+		}
+		stats := result["dates"].Stats
+		if stats == nil || stats.Count != 3 || stats.Min == nil || stats.Max == nil || *stats.Min >= *stats.Max {
+			t.Error(result)
+		}
+	})
+
+	t.Run("permission filtered", func(t *testing.T) {
+		result, err := query(secondOwnerToken, "devices", map[string]model.Aggregation{
+			"device_types":          {Type: model.AggregationTypeTerms, Field: "device_type_id"},
+			"distinct_device_types": {Type: model.AggregationTypeCardinality, Field: "device_type_id"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result["device_types"].Buckets) != 0 || *result["distinct_device_types"].Cardinality != 0 {
+			t.Error(result)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := query(testtoken, "devices", map[string]model.Aggregation{
+			"per_week": {Type: model.AggregationTypeDateHistogram, Field: "date"},
+		})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("sub aggregation named like bucket field", func(t *testing.T) {
+		_, err := query(testtoken, "devices", map[string]model.Aggregation{
+			"per_type": {Type: model.AggregationTypeTerms, Field: "device_type_id", Aggregations: map[string]model.Aggregation{
+				"doc_count": {Type: model.AggregationTypeCardinality, Field: "owner_id"},
+			}},
+		})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+}
+
+func TestScopedAggregations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "lamp 1", "device_type_id": "dt1", "owner_id": "o1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "lamp 2", "device_type_id": "dt1", "owner_id": "o2"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "sensor 1", "device_type_id": "dt2", "owner_id": "o2"}))
+	t.Run("create d4", saveTestDevice(w, resource, "d4", map[string]interface{}{"name": "sensor 2", "device_type_id": "dt3", "owner_id": "o1"}))
+
+	time.Sleep(2 * time.Second)
+
+	dt1Filter := &model.Selection{Condition: model.ConditionConfig{Feature: "features.device_type_id", Operation: model.QueryEqualOperation, Value: "dt1"}}
+	ownerAggregation := map[string]model.Aggregation{"owners": {Type: model.AggregationTypeTerms, Field: "owner_id"}}
+
+	t.Run("term aggregate with filter", func(t *testing.T) {
+		field := "features.owner_id"
+		result, _, err := q.Query(testtoken, model.QueryMessage{
+			Resource:         resource,
+			TermAggregate:    &field,
+			AggregationScope: &model.AggregationScope{Filter: dt1Filter},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected := []model.TermAggregationResultElement{{Term: "o1", Count: 1}, {Term: "o2", Count: 1}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Error(result)
+		}
+	})
+
+	t.Run("aggregations with search", func(t *testing.T) {
+		result, _, err := q.Query(testtoken, model.QueryMessage{
+			Resource:         resource,
+			Aggregations:     map[string]model.Aggregation{"device_types": {Type: model.AggregationTypeTerms, Field: "device_type_id"}},
+			AggregationScope: &model.AggregationScope{Search: "sensor"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected := map[string]model.AggregationValue{"device_types": {Buckets: []model.AggregationBucket{{Key: "dt2", Count: 1}, {Key: "dt3", Count: 1}}}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Error(result)
+		}
+	})
+
+	t.Run("find with facets", func(t *testing.T) {
+		result, _, err := q.Query(testtoken, model.QueryMessage{
+			Resource:     resource,
+			Find:         &model.QueryFind{QueryListCommons: model.QueryListCommons{Limit: 1, SortBy: "name"}, Search: "lamp"},
+			Aggregations: ownerAggregation,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		withFacets, ok := result.(model.FindWithFacets)
+		if !ok {
+			t.Errorf("%#v", result)
+			return
+		}
+		if withFacets.Total != 2 || len(withFacets.Result.([]map[string]interface{})) != 1 {
+			t.Error(withFacets)
+		}
+		expected := map[string]model.AggregationValue{"owners": {Buckets: []model.AggregationBucket{{Key: "o1", Count: 1}, {Key: "o2", Count: 1}}}}
+		if !reflect.DeepEqual(withFacets.Facets, expected) {
+			t.Error(withFacets.Facets)
+		}
+	})
+
+	t.Run("scope with find", func(t *testing.T) {
+		_, code, err := q.Query(testtoken, model.QueryMessage{
+			Resource:         resource,
+			Find:             &model.QueryFind{Search: "lamp"},
+			Aggregations:     ownerAggregation,
+			AggregationScope: &model.AggregationScope{Filter: dt1Filter},
+		})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(code, err)
+		}
+	})
+}
+
+func saveTestProcessModel(w *worker.Worker, id string, fields map[string]interface{}) func(t *testing.T) {
+	return func(t *testing.T) {
+		msg, err := json.Marshal(map[string]interface{}{
+			"command":      "PUT",
+			"id":           id,
+			"owner":        testTokenUser,
+			"processmodel": fields,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		command := model.CommandWrapper{}
+		err = json.Unmarshal(msg, &command)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = w.UpdateFeatures("processmodel", msg, command)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
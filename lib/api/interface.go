@@ -17,6 +17,7 @@
 package api
 
 import (
+	"context"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
 )
@@ -55,12 +56,21 @@ type Query interface {
 
 type V3 interface {
 	Query(token string, query model.QueryMessage) (result interface{}, code int, err error)
+	QueryBatch(token string, queries []model.QueryMessage) (result []model.QueryBatchResult, err error)
+	ExplainQuery(token string, request model.QueryExplainRequest) (result model.QueryExplainResult, err error)
 	List(token string, kind string, options model.ListOptions) (result []map[string]interface{}, err error)
+	ListWithTotal(token string, kind string, options model.ListOptions) (result model.WithTotal, err error)
 	Total(token string, kind string, options model.ListOptions) (result int64, err error)
+	CloseCursor(token string, kind string, cursor string) error
+	Stream(ctx context.Context, token string, kind string, options model.ListOptions, handler func(batch []map[string]interface{}) error) error
+	FederatedSearch(token string, request model.FederatedSearchRequest) (result model.FederatedSearchResult, err error)
+	Suggest(token string, kind string, options model.SuggestOptions) (result []model.Suggestion, err error)
+	SavedQuery(token string, kind string, name string, request model.SavedQueryRequest) (result interface{}, code int, err error)
 
 	CheckUserOrGroup(token string, kind string, resource string, rights string) (err error)
 
 	GetRights(token string, kind string, resource string) (result model.ResourceRights, err error)
+	GetRightsCacheStats(token string) (result model.RightsCacheStats, err error)
 
 	GetTermAggregation(token string, kind string, rights string, field string, limit int) (result []model.TermAggregationResultElement, err error)
 
@@ -624,7 +624,7 @@ func V1Endpoints(router *httprouter.Router, config configuration.Config, q Query
 
 		list, err := q.SearchList(token, kind, query, queryListCommons, &selection)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			http.Error(res, err.Error(), model.GetErrCode(err))
 			return
 		}
 		res.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -659,7 +659,7 @@ func V1Endpoints(router *httprouter.Router, config configuration.Config, q Query
 
 		list, err := q.SearchList(token, kind, query, queryListCommons, &selection)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			http.Error(res, err.Error(), model.GetErrCode(err))
 			return
 		}
 		res.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -693,7 +693,7 @@ func V1Endpoints(router *httprouter.Router, config configuration.Config, q Query
 
 		list, err := q.GetListWithSelection(token, kind, queryListCommons, selection)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			http.Error(res, err.Error(), model.GetErrCode(err))
 			return
 		}
 		res.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -727,7 +727,7 @@ func V1Endpoints(router *httprouter.Router, config configuration.Config, q Query
 
 		list, err := q.GetListWithSelection(token, kind, queryListCommons, selection)
 		if err != nil {
-			http.Error(res, err.Error(), http.StatusInternalServerError)
+			http.Error(res, err.Error(), model.GetErrCode(err))
 			return
 		}
 		res.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -154,7 +154,7 @@ func V2Endpoints(router *httprouter.Router, config configuration.Config, q Query
 			if query.Find.Limit == 0 {
 				query.Find.Limit = 100
 			}
-			if query.Find.SortBy == "" {
+			if query.Find.SortBy == "" && len(query.Find.Sort) == 0 {
 				query.Find.SortBy = "name"
 			}
 			if query.Find.Rights == "" {
@@ -195,7 +195,7 @@ func V2Endpoints(router *httprouter.Router, config configuration.Config, q Query
 			if query.ListIds.Limit == 0 {
 				query.ListIds.Limit = 100
 			}
-			if query.ListIds.SortBy == "" {
+			if query.ListIds.SortBy == "" && len(query.ListIds.Sort) == 0 {
 				query.ListIds.SortBy = "name"
 			}
 			if query.ListIds.Rights == "" {
@@ -213,7 +213,7 @@ func V2Endpoints(router *httprouter.Router, config configuration.Config, q Query
 		}
 
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), model.GetErrCode(err))
 			return
 		}
 
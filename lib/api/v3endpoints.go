@@ -18,16 +18,20 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/configuration"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
 	"github.com/SENERGY-Platform/permission-search/lib/rigthsproducer"
 	"github.com/julienschmidt/httprouter"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func init() {
@@ -57,41 +61,34 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 		json.NewEncoder(res).Encode(rights)
 	})
 
+	router.GET("/v3/administrate/rights-cache", func(res http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		token := auth.GetAuthToken(r)
+		stats, err := q.GetRightsCacheStats(token)
+		if err != nil {
+			http.Error(res, err.Error(), model.GetErrCode(err))
+			return
+		}
+		res.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(res).Encode(stats)
+	})
+
 	router.GET("/v3/resources/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		resource := params.ByName("resource")
 
-		search := request.URL.Query().Get("search")
-		selection := request.URL.Query().Get("filter")
-		ids := request.URL.Query().Get("ids")
-
 		token := auth.GetAuthToken(request)
 
-		queryListCommons, err := model.GetQueryListCommonsFromUrlQuery(request.URL.Query())
+		listOptions, err := getListOptionsFromUrlQuery(request.URL.Query())
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		listOptions := model.ListOptions{
-			QueryListCommons: queryListCommons,
-			TextSearch:       search,
-		}
-		if ids != "" {
-			listOptions.ListIds = strings.Split(ids, ",")
-		}
-		if selection != "" {
-			selectionParts := strings.Split(selection, ":")
-			if len(selectionParts) < 2 {
-				http.Error(writer, "the query parameter 'select' expects a value like 'feature_name:feature_value'", http.StatusBadRequest)
-				return
-			}
-			listOptions.Selection = &model.FeatureSelection{
-				Feature: selectionParts[0],
-				Value:   strings.Join(selectionParts[1:], ":"),
-			}
+		var result interface{}
+		if listOptions.WithTotal || listOptions.WithCursor || listOptions.DidYouMean {
+			result, err = q.ListWithTotal(token, resource, listOptions)
+		} else {
+			result, err = q.List(token, resource, listOptions)
 		}
-
-		result, err := q.List(token, resource, listOptions)
 		if err != nil {
 			http.Error(writer, err.Error(), model.GetErrCode(err))
 			return
@@ -104,34 +101,42 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 	router.GET("/v3/total/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		resource := params.ByName("resource")
 
-		search := request.URL.Query().Get("search")
-		selection := request.URL.Query().Get("filter")
-
 		token := auth.GetAuthToken(request)
 
-		queryListCommons, err := model.GetQueryListCommonsFromUrlQuery(request.URL.Query())
+		listOptions, err := getListOptionsFromUrlQuery(request.URL.Query())
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		listOptions := model.ListOptions{
-			QueryListCommons: queryListCommons,
-			TextSearch:       search,
+		result, err := q.Total(token, resource, listOptions)
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
 		}
-		if selection != "" {
-			selectionParts := strings.Split(selection, ":")
-			if len(selectionParts) < 2 {
-				http.Error(writer, "the query parameter 'select' expects a value like 'feature_name:feature_value'", http.StatusBadRequest)
-				return
-			}
-			listOptions.Selection = &model.FeatureSelection{
-				Feature: selectionParts[0],
-				Value:   strings.Join(selectionParts[1:], ":"),
-			}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
+	router.DELETE("/v3/cursors/:resource/:cursor", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		err := q.CloseCursor(auth.GetAuthToken(request), params.ByName("resource"), params.ByName("cursor"))
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
 		}
+		writer.WriteHeader(http.StatusOK)
+	})
 
-		result, err := q.Total(token, resource, listOptions)
+	router.POST("/v3/total/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		resource := params.ByName("resource")
+		token := auth.GetAuthToken(request)
+		totalRequest := model.TotalRequest{}
+		err := json.NewDecoder(request.Body).Decode(&totalRequest)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := q.Total(token, resource, totalRequest.ToListOptions())
 		if err != nil {
 			http.Error(writer, err.Error(), model.GetErrCode(err))
 			return
@@ -140,6 +145,58 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 		json.NewEncoder(writer).Encode(result)
 	})
 
+	router.GET("/v3/stream/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		resource := params.ByName("resource")
+		token := auth.GetAuthToken(request)
+
+		listOptions, err := getListOptionsFromUrlQuery(request.URL.Query())
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		//streams may take longer than the configured http_server_timeout
+		controller := http.NewResponseController(writer)
+		_ = controller.SetWriteDeadline(time.Time{})
+
+		//the status is sent with the first batch, so that errors before the first batch get a matching status code
+		started := false
+		encoder := json.NewEncoder(writer)
+		start := func() {
+			if !started {
+				started = true
+				writer.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+				writer.WriteHeader(http.StatusOK)
+			}
+		}
+		err = q.Stream(request.Context(), token, resource, listOptions, func(batch []map[string]interface{}) error {
+			start()
+			for _, element := range batch {
+				err := encoder.Encode(element)
+				if err != nil {
+					return err
+				}
+			}
+			err = controller.Flush()
+			if errors.Is(err, http.ErrNotSupported) {
+				return nil
+			}
+			return err
+		})
+		if err != nil && !started {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+		if err != nil {
+			//the status is already sent -> abort the response, so that the client sees an incomplete stream instead of a complete one
+			if request.Context().Err() == nil {
+				log.Println("ERROR: stream of", resource, "failed:", err)
+			}
+			panic(http.ErrAbortHandler)
+		}
+		start()
+	})
+
 	router.HEAD("/v3/resources/:resource/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		resource := params.ByName("resource")
 		id := params.ByName("id")
@@ -225,6 +282,63 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 		json.NewEncoder(writer).Encode(result)
 	})
 
+	router.POST("/v3/query-batch", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token := auth.GetAuthToken(request)
+		queries := []model.QueryMessage{}
+		err := json.NewDecoder(request.Body).Decode(&queries)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if config.Debug {
+			temp, _ := json.Marshal(queries)
+			log.Println("DEBUG:", auth.GetAuthToken(request), "\n", string(temp))
+		}
+
+		result, err := q.QueryBatch(token, queries)
+
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+
+		if config.Debug {
+			temp, _ := json.Marshal(result)
+			log.Println("DEBUG:", string(temp))
+		}
+
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
+	router.POST("/v3/explain/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token := auth.GetAuthToken(request)
+		explainRequest := model.QueryExplainRequest{}
+		err := json.NewDecoder(request.Body).Decode(&explainRequest)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pathResource := params.ByName("resource")
+		if explainRequest.Resource == "" {
+			explainRequest.Resource = pathResource
+		}
+		if explainRequest.Resource != pathResource {
+			http.Error(writer, "payload resource does not match path resource", http.StatusBadRequest)
+			return
+		}
+
+		result, err := q.ExplainQuery(token, explainRequest)
+
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
 	router.POST("/v3/query/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token := auth.GetAuthToken(request)
 		query := model.QueryMessage{}
@@ -265,6 +379,84 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 		json.NewEncoder(writer).Encode(result)
 	})
 
+	router.POST("/v3/saved-queries/:resource/:name", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token := auth.GetAuthToken(request)
+		savedQueryRequest := model.SavedQueryRequest{}
+		err := json.NewDecoder(request.Body).Decode(&savedQueryRequest)
+		if err != nil && !errors.Is(err, io.EOF) { //the payload is optional
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if config.Debug {
+			temp, _ := json.Marshal(savedQueryRequest)
+			log.Println("DEBUG:", auth.GetAuthToken(request), "\n", params.ByName("name"), string(temp))
+		}
+
+		result, code, err := q.SavedQuery(token, params.ByName("resource"), params.ByName("name"), savedQueryRequest)
+
+		if err != nil {
+			if code == 0 {
+				code = http.StatusInternalServerError
+			}
+			http.Error(writer, err.Error(), code)
+			return
+		}
+
+		if config.Debug {
+			temp, _ := json.Marshal(result)
+			log.Println("DEBUG:", string(temp))
+		}
+
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
+	router.GET("/v3/suggest/:resource", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		resource := params.ByName("resource")
+		token := auth.GetAuthToken(request)
+		options, err := model.GetSuggestOptionsFromUrlQuery(request.URL.Query())
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+		result, err := q.Suggest(token, resource, options)
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
+	router.POST("/v3/search", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token := auth.GetAuthToken(request)
+		search := model.FederatedSearchRequest{}
+		err := json.NewDecoder(request.Body).Decode(&search)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if config.Debug {
+			temp, _ := json.Marshal(search)
+			log.Println("DEBUG:", auth.GetAuthToken(request), "\n", string(temp))
+		}
+
+		result, err := q.FederatedSearch(token, search)
+
+		if err != nil {
+			http.Error(writer, err.Error(), model.GetErrCode(err))
+			return
+		}
+
+		if config.Debug {
+			temp, _ := json.Marshal(result)
+			log.Println("DEBUG:", string(temp))
+		}
+
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	})
+
 	router.GET("/v3/export/:resource", func(res http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		var err error
 		token := auth.GetAuthToken(r)
@@ -298,3 +490,35 @@ func V3Endpoints(router *httprouter.Router, config configuration.Config, q Query
 
 	return true
 }
+
+// getListOptionsFromUrlQuery reads the query-parameters of GET /v3/resources/:resource, /v3/total/:resource and /v3/stream/:resource
+func getListOptionsFromUrlQuery(queryParams url.Values) (listOptions model.ListOptions, err error) {
+	queryListCommons, err := model.GetQueryListCommonsFromUrlQuery(queryParams)
+	if err != nil {
+		return listOptions, err
+	}
+	listOptions = model.ListOptions{
+		QueryListCommons: queryListCommons,
+		TextSearch:       queryParams.Get("search"),
+		Expression:       queryParams.Get("q"),
+	}
+	if ids := queryParams.Get("ids"); ids != "" {
+		listOptions.ListIds = strings.Split(ids, ",")
+	}
+	if selection := queryParams.Get("filter"); selection != "" {
+		selectionParts := strings.Split(selection, ":")
+		if len(selectionParts) < 2 {
+			return listOptions, errors.New("the query parameter 'select' expects a value like 'feature_name:feature_value'")
+		}
+		listOptions.Selection = &model.FeatureSelection{
+			Feature: selectionParts[0],
+			Value:   strings.Join(selectionParts[1:], ":"),
+		}
+	}
+	err = listOptions.SetSearchOptionsFromUrlQuery(queryParams)
+	if err != nil {
+		return listOptions, err
+	}
+	err = listOptions.SetFilterFromUrlQuery(queryParams)
+	return listOptions, err
+}
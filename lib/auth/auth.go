@@ -17,7 +17,11 @@
 package auth
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"strings"
 )
 
 var GetAuthToken = jwt.GetAuthToken
@@ -25,3 +29,32 @@ var GetParsedToken = jwt.GetParsedToken
 var Parse = jwt.Parse
 
 type Token = jwt.Token
+
+// GetClaims returns all claims of the token payload
+// tokens without raw jwt string (e.g. constructed in tests) return the claims known to Token
+func GetClaims(token Token) (claims map[string]interface{}, err error) {
+	claims = map[string]interface{}{}
+	raw := token.Jwt()
+	if len(raw) > 7 && strings.ToLower(raw[:7]) == "bearer " {
+		raw = raw[7:]
+	}
+	if raw == "" {
+		temp, err := json.Marshal(token)
+		if err != nil {
+			return claims, err
+		}
+		err = json.Unmarshal(temp, &claims)
+		delete(claims, "__token")
+		return claims, err
+	}
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("invalid jwt structure")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, err
+	}
+	err = json.Unmarshal(payload, &claims)
+	return claims, err
+}
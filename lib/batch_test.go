@@ -0,0 +1,173 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "find_d1_name", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "d2_name", "device_type_id": "dt1"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "find_d3_name", "device_type_id": "dt2"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	termAggregate := "features.device_type_id"
+	queries := []client.QueryMessage{
+		{
+			Resource: resource,
+			Find: &client.QueryFind{
+				QueryListCommons: client.QueryListCommons{WithTotal: true, SortBy: "name"},
+				Search:           "find",
+			},
+		},
+		{
+			Resource:      resource,
+			TermAggregate: &termAggregate,
+		},
+		{
+			Resource: resource,
+			CheckIds: &client.QueryCheckIds{Ids: []string{"d1", "unknown"}, Rights: "r"},
+		},
+		{
+			Resource: resource,
+			Find: &client.QueryFind{
+				QueryListCommons: client.QueryListCommons{Limit: -1},
+			},
+		},
+		{
+			Resource: "unknown-resource",
+			Find:     &client.QueryFind{},
+		},
+	}
+
+	result, err := c.QueryBatch(testtoken, queries)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result) != len(queries) {
+		t.Error(result)
+		return
+	}
+
+	t.Run("find", func(t *testing.T) {
+		expected, code, err := q.Query(testtoken, queries[0])
+		if err != nil {
+			t.Error(code, err)
+			return
+		}
+		compareBatchResult(t, result[0], expected)
+	})
+
+	t.Run("term aggregation", func(t *testing.T) {
+		expected, code, err := q.Query(testtoken, queries[1])
+		if err != nil {
+			t.Error(code, err)
+			return
+		}
+		compareBatchResult(t, result[1], expected)
+	})
+
+	t.Run("check ids", func(t *testing.T) {
+		expected, code, err := q.Query(testtoken, queries[2])
+		if err != nil {
+			t.Error(code, err)
+			return
+		}
+		compareBatchResult(t, result[2], expected)
+	})
+
+	t.Run("invalid find", func(t *testing.T) {
+		if result[3].Status != http.StatusBadRequest || result[3].Error == "" {
+			t.Error(result[3])
+		}
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		if result[4].Status != http.StatusNotFound || result[4].Error == "" {
+			t.Error(result[4])
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		config.QueryBatchLimit = 2
+		defer func() {
+			config.QueryBatchLimit = 0
+		}()
+		_, err := c.QueryBatch(testtoken, queries[:2])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = c.QueryBatch(testtoken, queries[:3])
+		if err == nil || model.GetErrCode(err) != http.StatusBadRequest {
+			t.Error(err)
+		}
+	})
+}
+
+func compareBatchResult(t *testing.T, actual model.QueryBatchResult, expected interface{}) {
+	t.Helper()
+	if actual.Status != http.StatusOK {
+		t.Error(actual)
+		return
+	}
+	actualJson, err := json.Marshal(actual.Result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expectedJson, err := json.Marshal(expected)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var a, e interface{}
+	json.Unmarshal(actualJson, &a)
+	json.Unmarshal(expectedJson, &e)
+	if !reflect.DeepEqual(a, e) {
+		t.Error(string(actualJson), string(expectedJson))
+	}
+}
@@ -25,11 +25,23 @@ import (
 
 type Client interface {
 	Query(token string, query QueryMessage) (result interface{}, code int, err error)
+	QueryBatch(token string, queries []QueryMessage) (result []QueryBatchResult, err error)
+	ExplainQuery(token string, request QueryExplainRequest) (result QueryExplainResult, err error)
 	List(token string, kind string, options ListOptions) (result []map[string]interface{}, err error)
+	// ListWithTotal returns the total, next_cursor and did_you_mean suggestions of a list request
+	ListWithTotal(token string, kind string, options ListOptions) (result WithTotal[[]map[string]interface{}], err error)
 	Total(token string, kind string, options ListOptions) (result int64, err error)
+	// CloseCursor releases the snapshot of a next_cursor of a consistent request, which is not read to the last page
+	CloseCursor(token string, kind string, cursor string) (err error)
+	// OpenStream returns the ndjson body of GET /v3/stream/:resource; use Stream() to iterate over the elements
+	OpenStream(token string, kind string, options ListOptions) (body io.ReadCloser, err error)
+	FederatedSearch(token string, request FederatedSearchRequest) (result FederatedSearchResult, err error)
+	Suggest(token string, kind string, options SuggestOptions) (result []Suggestion, err error)
+	SavedQuery(token string, kind string, name string, request SavedQueryRequest) (result interface{}, code int, err error)
 
 	CheckUserOrGroup(token string, kind string, resource string, rights string) (err error)
 	GetRights(token string, kind string, resource string) (result model.ResourceRights, err error)
+	GetRightsCacheStats(token string) (result RightsCacheStats, err error)
 
 	GetTermAggregation(token string, kind string, rights string, field string, limit int) (result []model.TermAggregationResultElement, err error)
 
@@ -35,8 +35,10 @@ func Query[Result any](client Client, token string, query model.QueryMessage) (r
 }
 
 type WithTotal[Result any] struct {
-	Total  int64  `json:"total"`
-	Result Result `json:"result"`
+	Total       int64    `json:"total"`
+	Result      Result   `json:"result"`
+	NextCursor  string   `json:"next_cursor,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 func QueryWithTotal[Result any](client Client, token string, query model.QueryMessage) (result WithTotal[Result], code int, err error) {
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+)
+
+// Iterator walks through all results of a Find or ListIds query by following the next_cursor of each page.
+// the page size is defined by the Limit of the query.
+//
+//	it := client.Iterate[model.EntryResult](c, token, query)
+//	for it.Next() {
+//		element := it.Value()
+//	}
+//	if it.Err() != nil {...}
+//
+// Close releases the snapshot of a consistent query that is not read to the last page.
+type Iterator[Element any] struct {
+	client Client
+	token  string
+	query  model.QueryMessage
+	page   []Element
+	index  int
+	done   bool
+	err    error
+}
+
+func Iterate[Element any](client Client, token string, query model.QueryMessage) *Iterator[Element] {
+	it := &Iterator[Element]{client: client, token: token, query: query, index: -1}
+	commons := it.commons()
+	if commons == nil {
+		it.err = errors.New("iterator expects a find or list_ids query")
+		it.done = true
+		return it
+	}
+	commons.WithCursor = true
+	if commons.Limit <= 0 {
+		commons.Limit = 100
+	}
+	return it
+}
+
+func (this *Iterator[Element]) commons() *model.QueryListCommons {
+	if this.query.Find != nil {
+		find := *this.query.Find
+		this.query.Find = &find
+		return &this.query.Find.QueryListCommons
+	}
+	if this.query.ListIds != nil {
+		listIds := *this.query.ListIds
+		this.query.ListIds = &listIds
+		return &this.query.ListIds.QueryListCommons
+	}
+	return nil
+}
+
+// Next advances to the next element and returns false if no elements are left or an error occurred
+func (this *Iterator[Element]) Next() bool {
+	if this.index+1 < len(this.page) {
+		this.index++
+		return true
+	}
+	if this.done {
+		return false
+	}
+	page, _, err := Query[WithTotal[[]Element]](this.client, this.token, this.query)
+	if err != nil {
+		this.err = err
+		this.done = true
+		return false
+	}
+	this.page = page.Result
+	this.index = 0
+	if page.NextCursor == "" {
+		this.done = true
+	} else {
+		commons := this.commons()
+		commons.Offset = 0
+		commons.After = &model.ListAfter{Cursor: page.NextCursor}
+	}
+	return len(this.page) > 0
+}
+
+// Value returns the current element
+func (this *Iterator[Element]) Value() Element {
+	return this.page[this.index]
+}
+
+// Close releases the snapshot of a consistent query if the iteration stopped before the last page
+func (this *Iterator[Element]) Close() error {
+	commons := this.commons()
+	if this.done || commons == nil || !commons.Consistent || commons.After == nil || commons.After.Cursor == "" {
+		return nil
+	}
+	this.done = true
+	this.page = nil
+	return this.client.CloseCursor(this.token, this.query.Resource, commons.After.Cursor)
+}
+
+// Err returns the error that stopped the iteration
+func (this *Iterator[Element]) Err() error {
+	return this.err
+}
@@ -23,24 +23,58 @@ type ListOptions = model.ListOptions
 type FeatureSelection = model.FeatureSelection
 
 type QueryListCommons = model.QueryListCommons
+type SortField = model.SortField
 
 type QueryMessage = model.QueryMessage
+type QueryBatchResult = model.QueryBatchResult
+type QueryExplainRequest = model.QueryExplainRequest
+type QueryExplainResult = model.QueryExplainResult
+type QueryExplanation = model.QueryExplanation
+
+type Aggregation = model.Aggregation
+type AggregationValue = model.AggregationValue
+type AggregationBucket = model.AggregationBucket
+type AggregationStats = model.AggregationStats
+type AggregationScope = model.AggregationScope
+type FindWithFacets = model.FindWithFacets
 type QueryFind = model.QueryFind
 type QueryListIds = model.QueryListIds
 type QueryCheckIds = model.QueryCheckIds
 type ListAfter = model.ListAfter
 
+type FederatedSearchRequest = model.FederatedSearchRequest
+type FederatedSearchResult = model.FederatedSearchResult
+
+type SuggestOptions = model.SuggestOptions
+type Suggestion = model.Suggestion
+
+type SavedQueryRequest = model.SavedQueryRequest
+
+type RightsCacheStats = model.RightsCacheStats
+
 type QueryOperationType = model.QueryOperationType
 
 const (
-	QueryEqualOperation             QueryOperationType = model.QueryEqualOperation
-	QueryUnequalOperation           QueryOperationType = model.QueryUnequalOperation
-	QueryAnyValueInFeatureOperation QueryOperationType = model.QueryAnyValueInFeatureOperation
+	QueryEqualOperation                QueryOperationType = model.QueryEqualOperation
+	QueryUnequalOperation              QueryOperationType = model.QueryUnequalOperation
+	QueryAnyValueInFeatureOperation    QueryOperationType = model.QueryAnyValueInFeatureOperation
+	QueryGreaterOperation              QueryOperationType = model.QueryGreaterOperation
+	QueryGreaterEqualOperation         QueryOperationType = model.QueryGreaterEqualOperation
+	QueryLessOperation                 QueryOperationType = model.QueryLessOperation
+	QueryLessEqualOperation            QueryOperationType = model.QueryLessEqualOperation
+	QueryBetweenOperation              QueryOperationType = model.QueryBetweenOperation
+	QueryPrefixOperation               QueryOperationType = model.QueryPrefixOperation
+	QueryWildcardOperation             QueryOperationType = model.QueryWildcardOperation
+	QueryRegexpOperation               QueryOperationType = model.QueryRegexpOperation
+	QueryEqualCaseInsensitiveOperation QueryOperationType = model.QueryEqualCaseInsensitiveOperation
+	QueryInResourceQueryOperation      QueryOperationType = model.QueryInResourceQueryOperation
 )
 
 type ConditionConfig = model.ConditionConfig
 
 type Selection = model.Selection
+type NestedSelection = model.NestedSelection
+type ResourceQuery = model.ResourceQuery
 
 var ErrNotFound = model.ErrNotFound
 var ErrAccessDenied = model.ErrAccessDenied
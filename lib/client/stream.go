@@ -0,0 +1,100 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// StreamIterator reads the ndjson response of GET /v3/stream/:resource element by element.
+// a stream that is aborted by the server ends with io.ErrUnexpectedEOF.
+//
+//	it := client.Stream[map[string]interface{}](c, token, "devices", options)
+//	defer it.Close()
+//	for it.Next() {
+//		element := it.Value()
+//	}
+//	if it.Err() != nil {...}
+type StreamIterator[Element any] struct {
+	client  Client
+	token   string
+	kind    string
+	options ListOptions
+	body    io.ReadCloser
+	decoder *json.Decoder
+	value   Element
+	done    bool
+	err     error
+}
+
+// Stream returns an iterator over the elements of GET /v3/stream/:resource.
+// the server may cut off a stream after the first elements (e.g. on a database error or an expired snapshot);
+// Err() is then io.ErrUnexpectedEOF and the elements read so far are incomplete, so the stream has to be read again.
+func Stream[Element any](client Client, token string, kind string, options ListOptions) *StreamIterator[Element] {
+	return &StreamIterator[Element]{client: client, token: token, kind: kind, options: options}
+}
+
+// Next advances to the next element and returns false if no elements are left or an error occurred
+func (this *StreamIterator[Element]) Next() bool {
+	if this.done {
+		return false
+	}
+	if this.decoder == nil {
+		this.body, this.err = this.client.OpenStream(this.token, this.kind, this.options)
+		if this.err != nil {
+			this.done = true
+			return false
+		}
+		this.decoder = json.NewDecoder(this.body)
+	}
+	var value Element
+	err := this.decoder.Decode(&value)
+	if errors.Is(err, io.EOF) {
+		this.Close()
+		return false
+	}
+	if err != nil {
+		this.err = err
+		this.Close()
+		return false
+	}
+	this.value = value
+	return true
+}
+
+// Value returns the current element
+func (this *StreamIterator[Element]) Value() Element {
+	return this.value
+}
+
+// Err returns the error that stopped the iteration
+func (this *StreamIterator[Element]) Err() error {
+	return this.err
+}
+
+// Close releases the underlying response body; it is safe to call Close multiple times
+func (this *StreamIterator[Element]) Close() error {
+	this.done = true
+	if this.body == nil {
+		return nil
+	}
+	body := this.body
+	this.body = nil
+	return body.Close()
+}
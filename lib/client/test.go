@@ -19,6 +19,7 @@ package client
 import (
 	"errors"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"io"
 	"sync"
 )
 
@@ -61,21 +62,66 @@ func (this *TestClient) Query(token string, query QueryMessage) (result interfac
 	panic("implement me")
 }
 
+func (this *TestClient) QueryBatch(token string, queries []QueryMessage) (result []QueryBatchResult, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (this *TestClient) List(token string, kind string, options ListOptions) (result []map[string]interface{}, err error) {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (this *TestClient) ListWithTotal(token string, kind string, options ListOptions) (result WithTotal[[]map[string]interface{}], err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (this *TestClient) Total(token string, kind string, options ListOptions) (result int64, err error) {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (this *TestClient) CloseCursor(token string, kind string, cursor string) (err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (this *TestClient) OpenStream(token string, kind string, options ListOptions) (body io.ReadCloser, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (this *TestClient) FederatedSearch(token string, request FederatedSearchRequest) (result FederatedSearchResult, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (this *TestClient) Suggest(token string, kind string, options SuggestOptions) (result []Suggestion, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (this *TestClient) SavedQuery(token string, kind string, name string, request SavedQueryRequest) (result interface{}, code int, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (this *TestClient) ExplainQuery(token string, request QueryExplainRequest) (result QueryExplainResult, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (this *TestClient) CheckUserOrGroup(token string, kind string, resource string, rights string) (err error) {
 	//TODO implement me
 	panic("implement me")
 }
 
+func (this *TestClient) GetRightsCacheStats(token string) (result RightsCacheStats, err error) {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (this *TestClient) GetTermAggregation(token string, kind string, rights string, field string, limit int) (result []model.TermAggregationResultElement, err error) {
 	//TODO implement me
 	panic("implement me")
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -35,6 +36,16 @@ func (this *impl) GetRights(token string, kind string, resource string) (result
 	return
 }
 
+func (this *impl) GetRightsCacheStats(token string) (result RightsCacheStats, err error) {
+	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/administrate/rights-cache", nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[RightsCacheStats](req)
+	return
+}
+
 func (this *impl) Query(token string, query model.QueryMessage) (result interface{}, code int, err error) {
 	buf := &bytes.Buffer{}
 	err = json.NewEncoder(buf).Encode(query)
@@ -50,16 +61,81 @@ func (this *impl) Query(token string, query model.QueryMessage) (result interfac
 	return
 }
 
+func (this *impl) QueryBatch(token string, queries []QueryMessage) (result []QueryBatchResult, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(queries)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.baseUrl+"/v3/query-batch", buf)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[[]QueryBatchResult](req)
+	return
+}
+
+func (this *impl) ExplainQuery(token string, request QueryExplainRequest) (result QueryExplainResult, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(request)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.baseUrl+"/v3/explain/"+url.PathEscape(request.Resource), buf)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[QueryExplainResult](req)
+	return
+}
+
+// List returns the elements of GET /v3/resources/:resource; use ListWithTotal to get the total, next_cursor or suggestions
 func (this *impl) List(token string, kind string, options model.ListOptions) (result []map[string]interface{}, err error) {
 	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/resources/"+url.PathEscape(kind)+"?"+options.QueryValues().Encode(), nil)
 	if err != nil {
 		return result, err
 	}
 	req.Header.Set("Authorization", token)
-	result, _, err = do[[]map[string]interface{}](req)
+	temp, _, err := do[json.RawMessage](req)
+	if err != nil {
+		return result, err
+	}
+	//with_total, with_cursor and did_you_mean wrap the elements
+	if trimmed := bytes.TrimSpace(temp); len(trimmed) > 0 && trimmed[0] == '{' {
+		wrapper := WithTotal[[]map[string]interface{}]{}
+		err = json.Unmarshal(trimmed, &wrapper)
+		return wrapper.Result, err
+	}
+	err = json.Unmarshal(temp, &result)
+	return result, err
+}
+
+// ListWithTotal returns the wrapped result of GET /v3/resources/:resource; sets with_total if neither with_cursor nor did_you_mean are set
+func (this *impl) ListWithTotal(token string, kind string, options model.ListOptions) (result WithTotal[[]map[string]interface{}], err error) {
+	if !options.WithCursor && !options.DidYouMean {
+		options.WithTotal = true
+	}
+	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/resources/"+url.PathEscape(kind)+"?"+options.QueryValues().Encode(), nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[WithTotal[[]map[string]interface{}]](req)
 	return
 }
 
+func (this *impl) CloseCursor(token string, kind string, cursor string) (err error) {
+	req, err := http.NewRequest(http.MethodDelete, this.baseUrl+"/v3/cursors/"+url.PathEscape(kind)+"/"+url.PathEscape(cursor), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	_, err = head(req)
+	return err
+}
+
 func (this *impl) Total(token string, kind string, options model.ListOptions) (result int64, err error) {
 	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/total/"+url.PathEscape(kind)+"?"+options.QueryValues().Encode(), nil)
 	if err != nil {
@@ -70,6 +146,64 @@ func (this *impl) Total(token string, kind string, options model.ListOptions) (r
 	return
 }
 
+func (this *impl) OpenStream(token string, kind string, options model.ListOptions) (body io.ReadCloser, err error) {
+	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/stream/"+url.PathEscape(kind)+"?"+options.QueryValues().Encode(), nil)
+	if err != nil {
+		return body, err
+	}
+	req.Header.Set("Authorization", token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return body, err
+	}
+	if resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		temp, _ := io.ReadAll(resp.Body) //read error response end ensure that resp.Body is read to EOF
+		return body, model.GetErrFromCode(resp.StatusCode, string(temp))
+	}
+	return resp.Body, nil
+}
+
+func (this *impl) FederatedSearch(token string, request FederatedSearchRequest) (result FederatedSearchResult, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(request)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.baseUrl+"/v3/search", buf)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[FederatedSearchResult](req)
+	return
+}
+
+func (this *impl) Suggest(token string, kind string, options SuggestOptions) (result []Suggestion, err error) {
+	req, err := http.NewRequest(http.MethodGet, this.baseUrl+"/v3/suggest/"+url.PathEscape(kind)+"?"+options.QueryValues().Encode(), nil)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	result, _, err = do[[]Suggestion](req)
+	return
+}
+
+func (this *impl) SavedQuery(token string, kind string, name string, request SavedQueryRequest) (result interface{}, code int, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(request)
+	if err != nil {
+		return result, http.StatusInternalServerError, err
+	}
+	req, err := http.NewRequest(http.MethodPost, this.baseUrl+"/v3/saved-queries/"+url.PathEscape(kind)+"/"+url.PathEscape(name), buf)
+	if err != nil {
+		return result, http.StatusInternalServerError, err
+	}
+	req.Header.Set("Authorization", token)
+	result, code, err = do[interface{}](req)
+	return
+}
+
 func (this *impl) CheckUserOrGroup(token string, kind string, resource string, rights string) (err error) {
 	req, err := http.NewRequest(http.MethodHead, this.baseUrl+"/v3/resources/"+url.PathEscape(kind)+"/"+url.PathEscape(resource)+"?rights="+rights, nil)
 	if err != nil {
@@ -33,12 +33,17 @@ type Feature struct {
 	FirstOf                  []string
 	ResultListToFirstElement bool
 	ConcatListElementFields  []string
+	Suggest                  bool //optional; adds an autocomplete sub field to the mapping of the feature, which is used by GET /v3/suggest/:resource
 }
 
 type ResourceConfig struct {
-	Features           []Feature            `json:"features"`
-	Annotations        map[string][]Feature `json:"annotations"`
-	InitialGroupRights map[string]string    `json:"initial_group_rights"`
+	Features             []Feature            `json:"features"`
+	Annotations          map[string][]Feature `json:"annotations"`
+	InitialGroupRights   map[string]string    `json:"initial_group_rights"`
+	SearchMode           string               `json:"search_mode"`            //optional; default search_mode of text searches ('default', 'exact', 'prefix', 'fuzzy' or 'phrase')
+	Fuzziness            string               `json:"fuzziness"`              //optional; default fuzziness of the 'fuzzy' search_mode; default 'AUTO'
+	SearchBoosts         map[string]float64   `json:"search_boosts"`          //optional; relevance boost of fields with 'copy_to': 'feature_search' (e.g. {"name": 3})
+	DefaultExcludeFields []string             `json:"default_exclude_fields"` //optional; features or annotations that are not part of list results, unless requested by include_fields (e.g. ["svgXML"])
 }
 
 type ConfigStruct struct {
@@ -69,14 +74,26 @@ type ConfigStruct struct {
 	BulkWorkerCount                     int64                                        `json:"bulk_worker_count"`
 	UseBulkWorkerForAnnotations         bool                                         `json:"use_bulk_worker_for_annotations"`
 	EnableCombinedWildcardFeatureSearch bool                                         `json:"enable_combined_wildcard_feature_search"`
+	AllowExpensiveSelectionPatterns     bool                                         `json:"allow_expensive_selection_patterns"` //allows leading wildcards in 'wildcard' and 'regexp' selection conditions
+	SelectionPatternMaxLength           int64                                        `json:"selection_pattern_max_length"`       //max length of 'wildcard' and 'regexp' selection patterns; 0 disables the limit
+	SelectionPatternMaxQuantifiers      int64                                        `json:"selection_pattern_max_quantifiers"`  //max count of quantifiers (*, +, ?, {n,m}) in 'wildcard' and 'regexp' selection patterns; 0 disables the limit
+	InResourceQueryLimit                int64                                        `json:"in_resource_query_limit"`            //max count of ids an 'in_resource_query' selection condition may resolve to
+	RightsCacheSize                     int64                                        `json:"rights_cache_size"`                  //optional; max count of resource rights cached for access checks; 0 disables the cache
+	MaxOpenPitsPerUser                  int64                                        `json:"max_open_pits_per_user"`             //optional; max count of consistent list cursors (point in time snapshots) a user may have open at the same time; default 5
+	RightsCacheTtl                      string                                       `json:"rights_cache_ttl"`                   //optional; max age of cached resource rights; default 30s
+	AggregationMaxSize                  int64                                        `json:"aggregation_max_size"`               //optional; max size of 'terms' aggregations; default 1000
+	AggregationMaxDepth                 int64                                        `json:"aggregation_max_depth"`              //optional; max count of nested aggregation levels (aggregations with sub aggregations); default 3
+	CursorSecret                        string                                       `json:"cursor_secret"`                      //optional; key to sign list cursors; has to be the same for all instances; default: random key per instance
+	QueryBatchLimit                     int64                                        `json:"query_batch_limit"`                  //optional; max count of query messages in one POST /v3/query-batch request; default 100
 
 	JwtPubRsa string `json:"jwt_pub_rsa"`
 	ForceUser string `json:"force_user"`
 	ForceAuth string `json:"force_auth"`
 
-	Resources               map[string]ResourceConfig `json:"resources"`
-	ResourceList            []string                  `json:"-"`
-	AnnotationResourceIndex map[string][]string       `json:"-"`
+	Resources               map[string]ResourceConfig             `json:"resources"`
+	SavedQueries            map[string]map[string]json.RawMessage `json:"saved_queries"` //optional; resource -> name -> QueryFind template, executed by POST /v3/saved-queries/:resource/:name; condition refs like 'param.<name>' are filled with the request params
+	ResourceList            []string                              `json:"-"`
+	AnnotationResourceIndex map[string][]string                   `json:"-"`
 
 	GroupId string `json:"group_id"`
 
@@ -32,3 +32,13 @@ func getAnnotationResourceIndex(c Config) (result map[string][]string) {
 	}
 	return result
 }
+
+// SuggestFeatures returns the names of the features with Suggest, in the configured order
+func (this ResourceConfig) SuggestFeatures() (result []string) {
+	for _, feature := range this.Features {
+		if feature.Suggest {
+			result = append(result, feature.Name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/query"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCursorPagination(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "a"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "b"}))
+	t.Run("create dg3", saveTestDeviceGroup(w, resource, "dg3", map[string]interface{}{"name": "b"}))
+	t.Run("create dg4", saveTestDeviceGroup(w, resource, "dg4", map[string]interface{}{"name": "c"}))
+	t.Run("create dg5", saveTestDeviceGroup(w, resource, "dg5", map[string]interface{}{"name": "d"}))
+
+	time.Sleep(2 * time.Second)
+
+	options := model.ListOptions{QueryListCommons: model.QueryListCommons{
+		Limit:      2,
+		Rights:     "r",
+		SortBy:     "name",
+		SortDesc:   true,
+		WithCursor: true,
+	}}
+
+	t.Run("list pages", func(t *testing.T) {
+		ids := []string{}
+		current := options
+		for i := 0; i < 10; i++ {
+			page, err := q.ListWithTotal(testtoken, resource, current)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, element := range page.Result.([]map[string]interface{}) {
+				ids = append(ids, element["id"].(string))
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			current.After = &model.ListAfter{Cursor: page.NextCursor}
+		}
+		expected := []string{"dg5", "dg4", "dg3", "dg2", "dg1"}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Error(ids, expected)
+		}
+	})
+
+	t.Run("query pages", func(t *testing.T) {
+		ids := []string{}
+		find := model.QueryFind{QueryListCommons: options.QueryListCommons}
+		for i := 0; i < 10; i++ {
+			temp, _, err := q.Query(testtoken, model.QueryMessage{Resource: resource, Find: &find})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			page := temp.(query.WithTotal)
+			for _, element := range page.Result.([]map[string]interface{}) {
+				ids = append(ids, element["id"].(string))
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			find.After = &model.ListAfter{Cursor: page.NextCursor}
+		}
+		expected := []string{"dg5", "dg4", "dg3", "dg2", "dg1"}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Error(ids, expected)
+		}
+	})
+
+	t.Run("consistent pages", func(t *testing.T) {
+		ids := []string{}
+		current := options
+		current.Consistent = true
+		for i := 0; i < 10; i++ {
+			page, err := q.ListWithTotal(testtoken, resource, current)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, element := range page.Result.([]map[string]interface{}) {
+				ids = append(ids, element["id"].(string))
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			if i == 0 {
+				//written after the snapshot -> not part of the result
+				saveTestDeviceGroup(w, resource, "dg6", map[string]interface{}{"name": "a"})(t)
+				time.Sleep(2 * time.Second)
+			}
+			current.After = &model.ListAfter{Cursor: page.NextCursor}
+		}
+		expected := []string{"dg5", "dg4", "dg3", "dg2", "dg1"}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Error(ids, expected)
+		}
+	})
+
+	t.Run("consistent without cursor", func(t *testing.T) {
+		current := options
+		current.WithCursor = false
+		current.Consistent = true
+		_, err := q.ListWithTotal(testtoken, resource, current)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("close consistent cursor", func(t *testing.T) {
+		current := options
+		current.Consistent = true
+		page, err := q.ListWithTotal(testtoken, resource, current)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = q.CloseCursor(secondOwnerToken, resource, page.NextCursor)
+		if !errors.Is(err, model.ErrAccessDenied) {
+			t.Error(err)
+			return
+		}
+		err = q.CloseCursor(testtoken, resource, page.NextCursor)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current.After = &model.ListAfter{Cursor: page.NextCursor}
+		_, err = q.ListWithTotal(testtoken, resource, current)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("open consistent cursor limit", func(t *testing.T) {
+		config.MaxOpenPitsPerUser = 1
+		defer func() {
+			config.MaxOpenPitsPerUser = 0
+		}()
+		q, err := query.New(config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current := options
+		current.Consistent = true
+		page, err := q.ListWithTotal(testtoken, resource, current)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = q.ListWithTotal(testtoken, resource, current)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+		_, err = q.ListWithTotal(secondOwnerToken, resource, current)
+		if err != nil {
+			t.Error(err)
+		}
+		err = q.CloseCursor(testtoken, resource, page.NextCursor)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = q.ListWithTotal(testtoken, resource, current)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		current := options
+		current.After = &model.ListAfter{Cursor: "foo"}
+		_, err := q.ListWithTotal(testtoken, resource, current)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("cursor with changed sort", func(t *testing.T) {
+		page, err := q.ListWithTotal(testtoken, resource, options)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current := options
+		current.SortDesc = false
+		current.After = &model.ListAfter{Cursor: page.NextCursor}
+		_, err = q.ListWithTotal(testtoken, resource, current)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+}
+
+func TestCursorPaginationMissingSortField(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	for _, id := range []string{"d1", "d2", "d3", "d4", "d5"} {
+		t.Run("create "+id, saveTestDevice(w, resource, id, map[string]interface{}{"name": id}))
+	}
+	time.Sleep(2 * time.Second)
+
+	//d1, d3 and d5 have no annotations.connected -> opensearch returns Long.MAX_VALUE/Long.MIN_VALUE as sort value
+	t.Run("connect d2", func(t *testing.T) {
+		err := w.HandleAnnotationMsg("device_log", resource, []byte(`{"id": "d2", "connected": true}`))
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("disconnect d4", func(t *testing.T) {
+		err := w.HandleAnnotationMsg("device_log", resource, []byte(`{"id": "d4", "connected": false}`))
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	time.Sleep(2 * time.Second)
+
+	listPages := func(desc bool, expected []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			current := model.ListOptions{QueryListCommons: model.QueryListCommons{
+				Limit:      2,
+				Rights:     "r",
+				SortBy:     "annotations.connected",
+				SortDesc:   desc,
+				WithCursor: true,
+			}}
+			ids := []string{}
+			for i := 0; i < 10; i++ {
+				page, err := q.ListWithTotal(testtoken, resource, current)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for _, element := range page.Result.([]map[string]interface{}) {
+					ids = append(ids, element["id"].(string))
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				current.After = &model.ListAfter{Cursor: page.NextCursor}
+			}
+			if !reflect.DeepEqual(ids, expected) {
+				t.Error(ids, expected)
+			}
+		}
+	}
+
+	t.Run("asc", listPages(false, []string{"d4", "d2", "d1", "d3", "d5"}))
+	t.Run("desc", listPages(true, []string{"d2", "d4", "d5", "d3", "d1"}))
+}
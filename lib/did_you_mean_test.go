@@ -0,0 +1,134 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDidYouMean(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "Kitchen Lamp"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "Kitchen Sensor"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	find := func(token string, search string, expectedTotal int64, expectedSuggestions []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, _, err := client.Query[client.WithTotal[[]map[string]interface{}]](c, token, client.QueryMessage{
+				Resource: resource,
+				Find: &client.QueryFind{
+					QueryListCommons: client.QueryListCommons{Limit: 10},
+					Search:           search,
+					DidYouMean:       true,
+				},
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if result.Total != expectedTotal {
+				t.Error(result.Total, expectedTotal)
+			}
+			if !reflect.DeepEqual(result.Suggestions, expectedSuggestions) {
+				t.Error(result.Suggestions, expectedSuggestions)
+			}
+		}
+	}
+
+	t.Run("find with hits", find(testtoken, "lamp", 1, nil))
+	t.Run("find misspelled", find(testtoken, "lmap", 0, []string{"lamp"}))
+	t.Run("find misspelled without rights", find(secondOwnerToken, "lmap", 0, nil))
+
+	t.Run("list misspelled", func(t *testing.T) {
+		query := url.Values{}
+		query.Set("search", "sensro")
+		query.Set("did_you_mean", "true")
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:"+config.ServerPort+"/v3/resources/"+resource+"?"+query.Encode(), nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header.Set("Authorization", testtoken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Error(resp.StatusCode)
+			return
+		}
+		result := client.WithTotal[[]map[string]interface{}]{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Total != 0 || !reflect.DeepEqual(result.Suggestions, []string{"sensor"}) {
+			t.Error(result)
+		}
+	})
+
+	t.Run("client list with total misspelled", func(t *testing.T) {
+		result, err := c.ListWithTotal(testtoken, resource, client.ListOptions{QueryListCommons: client.QueryListCommons{Limit: 10}, TextSearch: "sensro", DidYouMean: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Total != 0 || len(result.Result) != 0 || !reflect.DeepEqual(result.Suggestions, []string{"sensor"}) {
+			t.Error(result)
+		}
+	})
+
+	t.Run("client list did_you_mean with hits", func(t *testing.T) {
+		result, err := c.List(testtoken, resource, client.ListOptions{QueryListCommons: client.QueryListCommons{Limit: 10}, TextSearch: "sensor", DidYouMean: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 || result[0]["id"] != "d2" {
+			t.Error(result)
+		}
+	})
+}
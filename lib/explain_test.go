@@ -0,0 +1,197 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryExplain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "d1", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "d2", "device_type_id": "dt2"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	query := client.QueryMessage{
+		Resource: resource,
+		Find: &client.QueryFind{
+			QueryListCommons: client.QueryListCommons{Limit: 5, Offset: 2, SortBy: "name"},
+			Filter: &client.Selection{Condition: client.ConditionConfig{
+				Feature:   "features.device_type_id",
+				Operation: client.QueryEqualOperation,
+				Value:     "dt1",
+			}},
+		},
+	}
+
+	t.Run("non admin", func(t *testing.T) {
+		_, err := c.ExplainQuery(testtoken, client.QueryExplainRequest{QueryMessage: query})
+		if err == nil || model.GetErrCode(err) != 403 {
+			t.Error(err)
+		}
+	})
+
+	t.Run("non admin in debug mode", func(t *testing.T) {
+		debug := config.Debug
+		config.Debug = true
+		defer func() {
+			config.Debug = debug
+		}()
+		_, err := q.ExplainQuery(testtoken, model.QueryExplainRequest{QueryMessage: query, ExplainId: "d1"})
+		if model.GetErrCode(err) != 403 {
+			t.Error(err)
+		}
+	})
+
+	t.Run("body", func(t *testing.T) {
+		result, err := c.ExplainQuery(admintoken, client.QueryExplainRequest{QueryMessage: query})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Index != resource || result.Explanation != nil {
+			t.Error(result)
+			return
+		}
+		temp, _ := json.Marshal(result.Body)
+		body := string(temp)
+		for _, expected := range []string{`"size":5`, `"from":2`, `"features.name"`, `"features.device_type_id":"dt1"`, `"read_users"`} {
+			if !strings.Contains(body, expected) {
+				t.Error(expected, body)
+			}
+		}
+	})
+
+	t.Run("explain match", func(t *testing.T) {
+		result, err := c.ExplainQuery(admintoken, client.QueryExplainRequest{QueryMessage: query, ExplainId: "d1"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Explanation == nil || !result.Explanation.Matched || len(result.Explanation.Details) == 0 {
+			t.Error(result.Explanation)
+		}
+	})
+
+	t.Run("explain mismatch", func(t *testing.T) {
+		result, err := c.ExplainQuery(admintoken, client.QueryExplainRequest{QueryMessage: query, ExplainId: "d2"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Explanation == nil || result.Explanation.Matched {
+			t.Error(result.Explanation)
+		}
+	})
+
+	t.Run("multiple parts", func(t *testing.T) {
+		_, err := c.ExplainQuery(admintoken, client.QueryExplainRequest{QueryMessage: client.QueryMessage{
+			Resource: resource,
+			Find:     query.Find,
+			CheckIds: &client.QueryCheckIds{Ids: []string{"d1"}, Rights: "r"},
+		}})
+		if err == nil || model.GetErrCode(err) != 400 {
+			t.Error(err)
+		}
+	})
+
+	t.Run("same body as query", func(t *testing.T) {
+		recorder := &searchBodyRecorder{Interface: q.GetClient().Transport}
+		q.GetClient().Transport = recorder
+		defer func() {
+			q.GetClient().Transport = recorder.Interface
+		}()
+		for name, message := range map[string]model.QueryMessage{
+			"find":      query,
+			"list_ids":  {Resource: resource, ListIds: &model.QueryListIds{QueryListCommons: model.QueryListCommons{Limit: 1, SortBy: "name"}, Ids: []string{"d1", "d2"}}},
+			"check_ids": {Resource: resource, CheckIds: &model.QueryCheckIds{Ids: []string{"d1", "d2"}, Rights: "r"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				recorder.Last = nil
+				_, _, err := q.Query(admintoken, message)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				var sent interface{}
+				err = json.Unmarshal(recorder.Last, &sent)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				result, err := q.ExplainQuery(admintoken, model.QueryExplainRequest{QueryMessage: message})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				temp, _ := json.Marshal(result.Body)
+				var explained interface{}
+				_ = json.Unmarshal(temp, &explained)
+				if !reflect.DeepEqual(sent, explained) {
+					t.Errorf("\n%#v\n%#v", sent, explained)
+				}
+			})
+		}
+	})
+}
+
+// searchBodyRecorder remembers the body of the last _search request
+type searchBodyRecorder struct {
+	opensearchtransport.Interface
+	Last []byte
+}
+
+func (this *searchBodyRecorder) Perform(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/_search") && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		this.Last = body
+		req.Body = io.NopCloser(strings.NewReader(string(body)))
+	}
+	return this.Interface.Perform(req)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFederatedSearch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "lamp", "description": "foo"}))
+	t.Run("create dt2", saveTestDeviceType(w, "dt2", map[string]interface{}{"name": "bar", "description": "lamp"}))
+	t.Run("create dt3", saveTestDeviceType(w, "dt3", map[string]interface{}{"name": "batz", "description": "sensor"}))
+	t.Run("create dg1", saveTestDeviceGroup(w, "device-groups", "dg1", map[string]interface{}{"name": "lamp"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, "device-groups", "dg2", map[string]interface{}{"name": "sensor"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	t.Run("grouped", func(t *testing.T) {
+		result, err := c.FederatedSearch(testtoken, client.FederatedSearchRequest{
+			Resources: []string{"device-types", "device-groups"},
+			Find:      client.QueryFind{Search: "lamp"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if total := result.Resources["device-types"].Total; total != 2 {
+			t.Error(total, result)
+		}
+		if total := result.Resources["device-groups"].Total; total != 1 {
+			t.Error(total, result)
+		}
+		if len(result.Resources["device-types"].Result) != 2 || len(result.Resources["device-groups"].Result) != 1 {
+			t.Error(result)
+		}
+		if len(result.Merged) != 0 {
+			t.Error(result.Merged)
+		}
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		result, err := c.FederatedSearch(testtoken, client.FederatedSearchRequest{
+			Resources:    []string{"device-types", "device-groups"},
+			Find:         client.QueryFind{Search: "lamp", QueryListCommons: client.QueryListCommons{Limit: 2}},
+			MergeByScore: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result.Merged) != 2 {
+			t.Error(result.Merged)
+			return
+		}
+		if result.Merged[0].Score < result.Merged[1].Score {
+			t.Error(result.Merged)
+		}
+		for _, element := range result.Merged {
+			if element.Resource != "device-types" && element.Resource != "device-groups" {
+				t.Error(element)
+			}
+		}
+	})
+
+	t.Run("merged with sort", func(t *testing.T) {
+		result, err := c.FederatedSearch(testtoken, client.FederatedSearchRequest{
+			Resources:    []string{"device-types", "device-groups"},
+			Find:         client.QueryFind{Search: "lamp", QueryListCommons: client.QueryListCommons{SortBy: "name"}},
+			MergeByScore: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result.Merged) != 3 {
+			t.Error(result.Merged)
+			return
+		}
+		for _, element := range result.Merged {
+			if element.Score <= 0 {
+				t.Error(element)
+			}
+		}
+	})
+
+	t.Run("permission filtered", func(t *testing.T) {
+		result, err := c.FederatedSearch(secondOwnerToken, client.FederatedSearchRequest{
+			Resources: []string{"device-types", "device-groups"},
+			Find:      client.QueryFind{Search: "lamp"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for kind, resourceResult := range result.Resources {
+			if resourceResult.Total != 0 || len(resourceResult.Result) != 0 {
+				t.Error(kind, resourceResult)
+			}
+		}
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		_, err := c.FederatedSearch(testtoken, client.FederatedSearchRequest{
+			Resources: []string{"device-types", "foo"},
+			Find:      client.QueryFind{Search: "lamp"},
+		})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("cursor not supported", func(t *testing.T) {
+		_, err := c.FederatedSearch(testtoken, client.FederatedSearchRequest{
+			Resources: []string{"device-types"},
+			Find:      client.QueryFind{Search: "lamp", QueryListCommons: client.QueryListCommons{WithCursor: true}},
+		})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+}
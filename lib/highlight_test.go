@@ -0,0 +1,126 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchHighlight(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "foo", "description": "a smart lamp with dimmer"}))
+	t.Run("create dt2", saveTestDeviceType(w, "dt2", map[string]interface{}{"name": "dimmer lamp", "description": "bar"}))
+	t.Run("create dt3", saveTestDeviceType(w, "dt3", map[string]interface{}{"name": "sensor", "description": "batz"}))
+
+	time.Sleep(2 * time.Second)
+
+	find := func(search string, highlight bool) ([]map[string]interface{}, error) {
+		result, _, err := q.Query(testtoken, model.QueryMessage{
+			Resource: "device-types",
+			Find: &model.QueryFind{
+				QueryListCommons: model.QueryListCommons{SortBy: "id"},
+				Search:           search,
+				Highlight:        highlight,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.([]map[string]interface{}), nil
+	}
+
+	t.Run("highlight", func(t *testing.T) {
+		result, err := find("dimmer", true)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		actual := map[string]interface{}{}
+		for _, element := range result {
+			actual[element["id"].(string)] = element["highlight"]
+		}
+		expected := map[string]interface{}{
+			"dt1": map[string][]string{"description": {"a smart lamp with <em>dimmer</em>"}},
+			"dt2": map[string][]string{"name": {"<em>dimmer</em> lamp"}},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%#v", actual)
+		}
+	})
+
+	t.Run("without highlight", func(t *testing.T) {
+		result, err := find("dimmer", false)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 2 {
+			t.Error(len(result))
+			return
+		}
+		for _, element := range result {
+			if _, ok := element["highlight"]; ok {
+				t.Error("unexpected highlight", element)
+			}
+		}
+	})
+
+	t.Run("highlight without search", func(t *testing.T) {
+		_, code, err := q.Query(testtoken, model.QueryMessage{
+			Resource: "device-types",
+			Find:     &model.QueryFind{Highlight: true},
+		})
+		if !errors.Is(err, model.ErrBadRequest) || code != http.StatusBadRequest {
+			t.Error(code, err)
+		}
+	})
+
+	t.Run("list highlight", func(t *testing.T) {
+		result, err := q.List(testtoken, "device-types", model.ListOptions{
+			QueryListCommons: model.QueryListCommons{Limit: 10, Rights: "r", SortBy: "id"},
+			TextSearch:       "sensor",
+			Highlight:        true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 || !reflect.DeepEqual(result[0]["highlight"], map[string][]string{"name": {"<em>sensor</em>"}}) {
+			t.Errorf("%#v", result)
+		}
+	})
+}
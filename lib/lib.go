@@ -76,6 +76,10 @@ func StartGetComponents(parentctx context.Context, config configuration.Config,
 	}
 
 	if mode == Query || mode == Command || mode == Standalone {
+		err = worker.InitRightsCacheInvalidation(ctx, config, q.InvalidateRightsCache)
+		if err != nil {
+			return q, p, w, err
+		}
 		err = api.Start(ctx, config, q, p)
 		if err != nil {
 			return q, p, w, err
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListWithSelection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "kitchen lamp", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "kitchen sensor", "device_type_id": "dt2"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "living lamp", "device_type_id": "dt1"}))
+	t.Run("create d4", saveTestDevice(w, resource, "d4", map[string]interface{}{"name": "garage lamp", "device_type_id": "dt2"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	dt1 := &model.Selection{Condition: model.ConditionConfig{
+		Feature:   "features.device_type_id",
+		Operation: model.QueryEqualOperation,
+		Value:     "dt1",
+	}}
+
+	check := func(options client.ListOptions, expectedIds []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			options.Limit = 100
+			options.SortBy = "name"
+			list, err := c.List(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids := []string{}
+			for _, element := range list {
+				ids = append(ids, element["id"].(string))
+			}
+			if !reflect.DeepEqual(ids, expectedIds) {
+				t.Error(ids, expectedIds)
+			}
+			total, err := c.Total(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if total != int64(len(expectedIds)) {
+				t.Error(total, len(expectedIds))
+			}
+		}
+	}
+
+	t.Run("selection", check(client.ListOptions{Filter: dt1}, []string{"d1", "d3"}))
+	t.Run("selection and search", check(client.ListOptions{Filter: dt1, TextSearch: "kitchen"}, []string{"d1"}))
+	t.Run("selection and ids", check(client.ListOptions{Filter: dt1, ListIds: []string{"d1", "d2"}}, []string{"d1"}))
+	t.Run("search and ids", check(client.ListOptions{TextSearch: "lamp", ListIds: []string{"d1", "d2", "d4"}}, []string{"d4", "d1"}))
+	t.Run("ids", check(client.ListOptions{ListIds: []string{"d2", "d3"}}, []string{"d3", "d2"}))
+	t.Run("all filters", check(client.ListOptions{
+		Filter:     dt1,
+		Selection:  &client.FeatureSelection{Feature: "name", Value: "living lamp"},
+		Expression: `id != "d1"`,
+		TextSearch: "lamp",
+	}, []string{"d3"}))
+
+	t.Run("invalid selection json", testRequestWithToken(config, testtoken, "GET", "/v3/total/"+resource+"?selection=%7B", nil, http.StatusBadRequest, nil))
+
+	t.Run("post total", testRequestWithToken(config, testtoken, "POST", "/v3/total/"+resource, model.TotalRequest{Search: "lamp", Filter: dt1}, http.StatusOK, 2))
+}
@@ -16,7 +16,104 @@
 
 package model
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type TermAggregationResultElement struct {
 	Term  interface{} `json:"term"`
 	Count int64       `json:"count"`
 }
+
+type AggregationType string
+
+const (
+	AggregationTypeTerms         AggregationType = "terms"
+	AggregationTypeDateHistogram AggregationType = "date_histogram"
+	AggregationTypeStats         AggregationType = "stats"
+	AggregationTypeCardinality   AggregationType = "cardinality"
+)
+
+// Aggregation describes one named aggregation of QueryMessage.Aggregations
+// Field may be given without the 'features.' prefix; annotations need the 'annotations.' prefix
+type Aggregation struct {
+	Type  AggregationType `json:"type"`
+	Field string          `json:"field"`
+
+	// Size is the maximal count of buckets of AggregationTypeTerms; defaults to 100
+	Size int `json:"size,omitempty"`
+
+	// Interval of AggregationTypeDateHistogram; either a calendar unit (minute, hour, day, week, month, quarter, year)
+	// or a fixed interval (e.g. "12h" or "90m")
+	Interval string `json:"interval,omitempty"`
+
+	// Aggregations are computed per bucket of AggregationTypeTerms and AggregationTypeDateHistogram
+	Aggregations map[string]Aggregation `json:"aggregations,omitempty"`
+}
+
+// reservedSubAggregationNames are fields of OpenSearch buckets, which would be shadowed by sub aggregations with these names
+var reservedSubAggregationNames = []string{"key", "key_as_string", "doc_count"}
+
+func ValidateAggregations(aggregations map[string]Aggregation) error {
+	return validateAggregations(aggregations, false)
+}
+
+func validateAggregations(aggregations map[string]Aggregation, sub bool) error {
+	for name, aggregation := range aggregations {
+		if name == "" {
+			return fmt.Errorf("%w: aggregations need a name", ErrBadRequest)
+		}
+		if sub && slices.Contains(reservedSubAggregationNames, name) {
+			return fmt.Errorf("%w: sub aggregations may not be named %v", ErrBadRequest, strings.Join(reservedSubAggregationNames, ", "))
+		}
+		if aggregation.Field == "" {
+			return fmt.Errorf("%w: aggregation %v needs a field", ErrBadRequest, name)
+		}
+		if aggregation.Size < 0 {
+			return fmt.Errorf("%w: size of aggregation %v should be at least 0", ErrBadRequest, name)
+		}
+		switch aggregation.Type {
+		case AggregationTypeTerms:
+		case AggregationTypeDateHistogram:
+			if aggregation.Interval == "" {
+				return fmt.Errorf("%w: aggregation %v needs an interval", ErrBadRequest, name)
+			}
+		case AggregationTypeStats, AggregationTypeCardinality:
+			if len(aggregation.Aggregations) > 0 {
+				return fmt.Errorf("%w: aggregation %v of type %v may not have sub aggregations", ErrBadRequest, name, aggregation.Type)
+			}
+		default:
+			return fmt.Errorf("%w: unknown type '%v' of aggregation %v", ErrBadRequest, aggregation.Type, name)
+		}
+		err := validateAggregations(aggregation.Aggregations, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AggregationValue is the result of one Aggregation; only the fields of the aggregation type are set
+type AggregationValue struct {
+	Buckets     []AggregationBucket `json:"buckets,omitempty"`
+	Stats       *AggregationStats   `json:"stats,omitempty"`
+	Cardinality *int64              `json:"cardinality,omitempty"`
+}
+
+type AggregationBucket struct {
+	Key          interface{}                 `json:"key"`
+	KeyAsString  string                      `json:"key_as_string,omitempty"`
+	Count        int64                       `json:"count"`
+	Aggregations map[string]AggregationValue `json:"aggregations,omitempty"`
+}
+
+// AggregationStats contains no Min, Max, Avg and Sum if no resource has a value
+type AggregationStats struct {
+	Count int64    `json:"count"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Avg   *float64 `json:"avg"`
+	Sum   *float64 `json:"sum"`
+}
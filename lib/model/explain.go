@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "encoding/json"
+
+// QueryExplainRequest is the payload of POST /v3/explain/:resource
+// it is a QueryMessage with one of Find, ListIds, CheckIds, TermAggregate or Aggregations
+type QueryExplainRequest struct {
+	QueryMessage
+	ExplainId string `json:"explain_id,omitempty"` //optional; runs the OpenSearch _explain api for this resource id
+}
+
+// QueryExplainResult describes the OpenSearch request Query would send for a QueryMessage
+type QueryExplainResult struct {
+	Index       string                 `json:"index"`
+	Body        map[string]interface{} `json:"body"`
+	Explanation *QueryExplanation      `json:"explanation,omitempty"`
+}
+
+// QueryExplanation tells why the resource ExplainId did or did not match the query of a QueryExplainResult
+type QueryExplanation struct {
+	Id      string          `json:"id"`
+	Matched bool            `json:"matched"`
+	Details json.RawMessage `json:"details"` //explanation of OpenSearch
+}
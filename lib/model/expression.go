@@ -0,0 +1,321 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseSelectionExpression compiles a filter expression to a Selection
+// example: device_type_id == "abc" and (annotations.connected == true or not name == null)
+//
+//	expression: or-expression
+//	or:         and { "or" and }
+//	and:        unary { "and" unary }
+//	unary:      "not" unary | "(" expression ")" | condition
+//	condition:  field ( "==" | "!=" | ">" | ">=" | "<" | "<=" ) value
+//	            | field ( "prefix" | "wildcard" | "regexp" ) string
+//	            | field "in" "[" [ value { "," value } ] "]"
+//	value:      string | number | "true" | "false" | "null"
+//
+// fields without 'features.' or 'annotations.' prefix are features (except 'id'); strings use double or single quotes
+// keywords are case-insensitive; errors are bad requests and contain the 1-based position of the problem
+func ParseSelectionExpression(expression string) (result Selection, err error) {
+	tokens, err := lexSelectionExpression(expression)
+	if err != nil {
+		return result, err
+	}
+	parser := &selectionExpressionParser{tokens: tokens}
+	result, err = parser.parseOr()
+	if err != nil {
+		return result, err
+	}
+	if next := parser.peek(); next.kind != expressionTokenEnd {
+		return result, next.errorf("unexpected %v, expected 'and', 'or' or end of expression", next)
+	}
+	return result, nil
+}
+
+type expressionTokenKind int
+
+const (
+	expressionTokenEnd expressionTokenKind = iota
+	expressionTokenIdentifier
+	expressionTokenString
+	expressionTokenNumber
+	expressionTokenSymbol
+)
+
+type expressionToken struct {
+	kind     expressionTokenKind
+	text     string //identifiers and symbols as written; strings unquoted
+	position int    //1-based
+}
+
+func (this expressionToken) String() string {
+	switch this.kind {
+	case expressionTokenEnd:
+		return "end of expression"
+	case expressionTokenString:
+		return strconv.Quote(this.text)
+	default:
+		return "'" + this.text + "'"
+	}
+}
+
+func (this expressionToken) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: invalid expression at position %v: %v", ErrBadRequest, this.position, fmt.Sprintf(format, args...))
+}
+
+func (this expressionToken) isKeyword(keyword string) bool {
+	return this.kind == expressionTokenIdentifier && strings.EqualFold(this.text, keyword)
+}
+
+func (this expressionToken) isSymbol(symbol string) bool {
+	return this.kind == expressionTokenSymbol && this.text == symbol
+}
+
+var expressionSymbols = []string{"==", "!=", ">=", "<=", ">", "<", "(", ")", "[", "]", ","}
+
+func lexSelectionExpression(expression string) (result []expressionToken, err error) {
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			text := []rune{}
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' {
+					escape := i
+					i++
+					if i >= len(runes) {
+						break
+					}
+					switch runes[i] {
+					case '\\', '"', '\'':
+						text = append(text, runes[i])
+					case 'n':
+						text = append(text, '\n')
+					case 't':
+						text = append(text, '\t')
+					default:
+						return result, fmt.Errorf("%w: invalid expression at position %v: unknown escape sequence '\\%v'", ErrBadRequest, escape+1, string(runes[i]))
+					}
+					continue
+				}
+				text = append(text, runes[i])
+			}
+			if i >= len(runes) {
+				return result, fmt.Errorf("%w: invalid expression at position %v: unterminated string", ErrBadRequest, start+1)
+			}
+			i++
+			result = append(result, expressionToken{kind: expressionTokenString, text: string(text), position: start + 1})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			i++
+			//a sign is only part of the number as exponent sign (e.g. 1e-5)
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE", runes[i]) ||
+				(strings.ContainsRune("+-", runes[i]) && strings.ContainsRune("eE", runes[i-1]))) {
+				i++
+			}
+			result = append(result, expressionToken{kind: expressionTokenNumber, text: string(runes[start:i]), position: start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			result = append(result, expressionToken{kind: expressionTokenIdentifier, text: string(runes[start:i]), position: start + 1})
+		default:
+			symbol := ""
+			for _, candidate := range expressionSymbols {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					symbol = candidate
+					break
+				}
+			}
+			if symbol == "" {
+				return result, fmt.Errorf("%w: invalid expression at position %v: unexpected character '%v'", ErrBadRequest, start+1, string(r))
+			}
+			i += len([]rune(symbol))
+			result = append(result, expressionToken{kind: expressionTokenSymbol, text: symbol, position: start + 1})
+		}
+	}
+	if len(result) == 0 {
+		return result, fmt.Errorf("%w: invalid expression at position 1: empty expression", ErrBadRequest)
+	}
+	return append(result, expressionToken{kind: expressionTokenEnd, position: len(runes) + 1}), nil
+}
+
+type selectionExpressionParser struct {
+	tokens []expressionToken
+	index  int
+}
+
+func (this *selectionExpressionParser) peek() expressionToken {
+	return this.tokens[this.index]
+}
+
+func (this *selectionExpressionParser) next() expressionToken {
+	result := this.tokens[this.index]
+	if result.kind != expressionTokenEnd {
+		this.index++
+	}
+	return result
+}
+
+func (this *selectionExpressionParser) parseOr() (result Selection, err error) {
+	return this.parseList("or", this.parseAnd, func(list []Selection) Selection { return Selection{Or: list} })
+}
+
+func (this *selectionExpressionParser) parseAnd() (result Selection, err error) {
+	return this.parseList("and", this.parseUnary, func(list []Selection) Selection { return Selection{And: list} })
+}
+
+// parseList parses elements separated by keyword and combines them with combine, if there is more than one element
+func (this *selectionExpressionParser) parseList(keyword string, parseElement func() (Selection, error), combine func([]Selection) Selection) (result Selection, err error) {
+	element, err := parseElement()
+	if err != nil {
+		return result, err
+	}
+	list := []Selection{element}
+	for this.peek().isKeyword(keyword) {
+		this.next()
+		element, err = parseElement()
+		if err != nil {
+			return result, err
+		}
+		list = append(list, element)
+	}
+	if len(list) == 1 {
+		return list[0], nil
+	}
+	return combine(list), nil
+}
+
+func (this *selectionExpressionParser) parseUnary() (result Selection, err error) {
+	token := this.peek()
+	switch {
+	case token.isKeyword("not"):
+		this.next()
+		sub, err := this.parseUnary()
+		if err != nil {
+			return result, err
+		}
+		return Selection{Not: &sub}, nil
+	case token.isSymbol("("):
+		this.next()
+		result, err = this.parseOr()
+		if err != nil {
+			return result, err
+		}
+		if closing := this.next(); !closing.isSymbol(")") {
+			return result, closing.errorf("unexpected %v, expected ')' to close '(' at position %v", closing, token.position)
+		}
+		return result, nil
+	default:
+		return this.parseCondition()
+	}
+}
+
+func (this *selectionExpressionParser) parseCondition() (result Selection, err error) {
+	field := this.next()
+	if field.kind != expressionTokenIdentifier || field.isKeyword("and") || field.isKeyword("or") || field.isKeyword("not") {
+		return result, field.errorf("unexpected %v, expected a field name, 'not' or '('", field)
+	}
+	condition := ConditionConfig{Feature: getExpressionFeature(field.text)}
+	operator := this.next()
+	switch {
+	case operator.kind == expressionTokenSymbol && map[string]bool{"==": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true}[operator.text]:
+		condition.Operation = QueryOperationType(operator.text)
+		condition.Value, err = this.parseValue()
+	case operator.isKeyword("prefix") || operator.isKeyword("wildcard") || operator.isKeyword("regexp"):
+		condition.Operation = QueryOperationType(strings.ToLower(operator.text))
+		value := this.next()
+		if value.kind != expressionTokenString {
+			return result, value.errorf("unexpected %v, expected a string after '%v'", value, operator.text)
+		}
+		condition.Value = value.text
+	case operator.isKeyword("in"):
+		condition.Operation = QueryAnyValueInFeatureOperation
+		condition.Value, err = this.parseValueList()
+	default:
+		return result, operator.errorf("unexpected %v, expected an operator ('==', '!=', '>', '>=', '<', '<=', 'prefix', 'wildcard', 'regexp' or 'in')", operator)
+	}
+	if err != nil {
+		return result, err
+	}
+	return Selection{Condition: condition}, nil
+}
+
+func (this *selectionExpressionParser) parseValueList() (result []interface{}, err error) {
+	result = []interface{}{}
+	if opening := this.next(); !opening.isSymbol("[") {
+		return result, opening.errorf("unexpected %v, expected '[' after 'in'", opening)
+	}
+	if this.peek().isSymbol("]") {
+		this.next()
+		return result, nil
+	}
+	for {
+		value, err := this.parseValue()
+		if err != nil {
+			return result, err
+		}
+		result = append(result, value)
+		separator := this.next()
+		if separator.isSymbol("]") {
+			return result, nil
+		}
+		if !separator.isSymbol(",") {
+			return result, separator.errorf("unexpected %v, expected ',' or ']'", separator)
+		}
+	}
+}
+
+func (this *selectionExpressionParser) parseValue() (result interface{}, err error) {
+	token := this.next()
+	switch {
+	case token.kind == expressionTokenString:
+		return token.text, nil
+	case token.kind == expressionTokenNumber:
+		result, err = strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return result, token.errorf("invalid number %v", token)
+		}
+		return result, nil
+	case token.isKeyword("true"):
+		return true, nil
+	case token.isKeyword("false"):
+		return false, nil
+	case token.isKeyword("null"):
+		return nil, nil
+	default:
+		return result, token.errorf("unexpected %v, expected a string, number, 'true', 'false' or 'null'", token)
+	}
+}
+
+func getExpressionFeature(field string) string {
+	if field == "id" || strings.HasPrefix(field, "features.") || strings.HasPrefix(field, "annotations.") {
+		return field
+	}
+	return "features." + field
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSelectionExpression(t *testing.T) {
+	condition := func(feature string, operation QueryOperationType, value interface{}) Selection {
+		return Selection{Condition: ConditionConfig{Feature: feature, Operation: operation, Value: value}}
+	}
+	check := func(expression string, expected Selection) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := ParseSelectionExpression(expression)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("\n%#v\n%#v", result, expected)
+			}
+		}
+	}
+
+	t.Run("example", check(`device_type_id == "abc" and (annotations.connected == true or not name == null)`, Selection{And: []Selection{
+		condition("features.device_type_id", QueryEqualOperation, "abc"),
+		{Or: []Selection{
+			condition("annotations.connected", QueryEqualOperation, true),
+			{Not: &Selection{Condition: ConditionConfig{Feature: "features.name", Operation: QueryEqualOperation}}},
+		}},
+	}}))
+	t.Run("precedence", check(`a == 1 or b != 'x' and c >= -2.5`, Selection{Or: []Selection{
+		condition("features.a", QueryEqualOperation, float64(1)),
+		{And: []Selection{
+			condition("features.b", QueryUnequalOperation, "x"),
+			condition("features.c", QueryGreaterEqualOperation, -2.5),
+		}},
+	}}))
+	t.Run("keywords", check(`id IN ["a", "b"] AND name prefix "ki" and features.x wildcard "*\"y*"`, Selection{And: []Selection{
+		condition("id", QueryAnyValueInFeatureOperation, []interface{}{"a", "b"}),
+		condition("features.name", QueryPrefixOperation, "ki"),
+		condition("features.x", QueryWildcardOperation, `*"y*`),
+	}}))
+	t.Run("empty list", check(`a in []`, condition("features.a", QueryAnyValueInFeatureOperation, []interface{}{})))
+	t.Run("exponent", check(`a > -1.5e-3 and b < 2E+2`, Selection{And: []Selection{
+		condition("features.a", QueryGreaterOperation, -1.5e-3),
+		condition("features.b", QueryLessOperation, float64(200)),
+	}}))
+
+	checkError := func(expression string, expectedPosition string) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := ParseSelectionExpression(expression)
+			if err == nil {
+				t.Error("expected error")
+				return
+			}
+			if GetErrCode(err) != 400 || !strings.Contains(err.Error(), "at position "+expectedPosition+":") {
+				t.Error(err)
+			}
+		}
+	}
+	t.Run("empty", checkError(` `, "1"))
+	t.Run("missing value", checkError(`name ==`, "8"))
+	t.Run("missing operator", checkError(`name "x"`, "6"))
+	t.Run("unclosed parenthesis", checkError(`(a == 1 or b == 2`, "18"))
+	t.Run("unterminated string", checkError(`a == "abc`, "6"))
+	t.Run("unexpected character", checkError(`a = 1`, "3"))
+	t.Run("trailing token", checkError(`a == 1 b == 2`, "8"))
+	t.Run("keyword as field", checkError(`a == 1 and or == 2`, "12"))
+	t.Run("unknown escape sequence", checkError(`a == "x\qy"`, "8"))
+	t.Run("unknown escape sequence after multi byte character", checkError(`a == "ä\q"`, "8"))
+	t.Run("arithmetic", checkError(`a==1-2`, "5"))
+	t.Run("sign after number", checkError(`a == 1+2`, "7"))
+	t.Run("invalid number", checkError(`a == 1.2.3`, "6"))
+}
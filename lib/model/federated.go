@@ -0,0 +1,65 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "fmt"
+
+// FederatedSearchRequest searches multiple resource kinds with one request
+// Find is applied to each resource; pagination (limit, offset) and sort apply per resource
+type FederatedSearchRequest struct {
+	Resources []string  `json:"resources"`
+	Find      QueryFind `json:"find"`
+
+	// MergeByScore adds a 'merged' list to the result, with the elements of all resources ordered by score, limited by Find.Limit
+	MergeByScore bool `json:"merge_by_score,omitempty"`
+}
+
+func (this FederatedSearchRequest) Validate() error {
+	if len(this.Resources) == 0 {
+		return fmt.Errorf("%w: missing resources", ErrBadRequest)
+	}
+	seen := map[string]bool{}
+	for _, resource := range this.Resources {
+		if seen[resource] {
+			return fmt.Errorf("%w: duplicate resource %v", ErrBadRequest, resource)
+		}
+		seen[resource] = true
+	}
+	if this.Find.After != nil || this.Find.WithCursor || this.Find.Consistent {
+		return fmt.Errorf("%w: federated searches support only offset pagination", ErrBadRequest)
+	}
+	if err := this.Find.QueryListCommons.Validate(); err != nil {
+		return err
+	}
+	return this.Find.ValidateSearchOptions()
+}
+
+type FederatedSearchResult struct {
+	Resources map[string]FederatedSearchResourceResult `json:"resources"`
+	Merged    []FederatedSearchElement                 `json:"merged,omitempty"`
+}
+
+type FederatedSearchResourceResult struct {
+	Total  int64                    `json:"total"`
+	Result []map[string]interface{} `json:"result"`
+}
+
+type FederatedSearchElement struct {
+	Resource string                 `json:"resource"`
+	Score    float64                `json:"score"`
+	Element  map[string]interface{} `json:"element"`
+}
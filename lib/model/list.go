@@ -17,16 +17,28 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+// ListOptions describe the resources of GET /v3/resources/:resource and /v3/total/:resource
+// ListIds, TextSearch, Selection, Expression and Filter may be combined; a resource has to match all of them
 type ListOptions struct {
 	QueryListCommons
 	ListIds    []string
 	TextSearch string
 	Selection  *FeatureSelection
+	Expression string     //filter expression, see ParseSelectionExpression (e.g. `device_type_id == "abc" and not name == null`)
+	Filter     *Selection //query-parameter 'selection' as json (e.g. {"condition": {"feature": "features.name", "operation": "==", "value": "foo"}})
+	Highlight  bool       //only usable with TextSearch
+	SearchMode SearchMode //only usable with TextSearch
+	Fuzziness  string     //only usable with TextSearch
+	//only usable with TextSearch; restricts the search to these fields; may contain boosts (e.g. "name^3")
+	SearchFields []string
+	DidYouMean   bool //only usable with TextSearch; ListWithTotal returns spelling suggestions if the search has no hits
 }
 
 type FeatureSelection struct {
@@ -45,42 +57,174 @@ func (this ListOptions) QueryValues() url.Values {
 	if this.Selection != nil {
 		result["filter"] = []string{this.Selection.Feature + ":" + this.Selection.Value}
 	}
+	if this.Expression != "" {
+		result["q"] = []string{this.Expression}
+	}
+	if this.Filter != nil {
+		filter, _ := json.Marshal(this.Filter)
+		result["selection"] = []string{string(filter)}
+	}
+	if this.Highlight {
+		result["highlight"] = []string{"true"}
+	}
+	if this.SearchMode != "" {
+		result["search_mode"] = []string{this.SearchMode}
+	}
+	if this.Fuzziness != "" {
+		result["fuzziness"] = []string{this.Fuzziness}
+	}
+	if len(this.SearchFields) > 0 {
+		result["search_fields"] = []string{strings.Join(this.SearchFields, ",")}
+	}
+	if this.DidYouMean {
+		result["did_you_mean"] = []string{"true"}
+	}
 	return result
 }
 
-func (this ListOptions) Validate() error {
-	_, err := this.Mode()
-	if err != nil {
-		return err
+// SetSearchOptionsFromUrlQuery reads the query-parameters 'highlight', 'search_mode', 'fuzziness', 'search_fields' and 'did_you_mean'
+func (this *ListOptions) SetSearchOptionsFromUrlQuery(queryParams url.Values) (err error) {
+	if highlight := queryParams.Get("highlight"); highlight != "" {
+		this.Highlight, err = strconv.ParseBool(highlight)
+		if err != nil {
+			return fmt.Errorf("%w: invalid highlight value: %v", ErrBadRequest, err.Error())
+		}
 	}
-	return this.QueryListCommons.Validate()
+	if didYouMean := queryParams.Get("did_you_mean"); didYouMean != "" {
+		this.DidYouMean, err = strconv.ParseBool(didYouMean)
+		if err != nil {
+			return fmt.Errorf("%w: invalid did_you_mean value: %v", ErrBadRequest, err.Error())
+		}
+	}
+	this.SearchMode = queryParams.Get("search_mode")
+	this.Fuzziness = queryParams.Get("fuzziness")
+	if searchFields := queryParams.Get("search_fields"); searchFields != "" {
+		this.SearchFields = strings.Split(searchFields, ",")
+	}
+	return nil
 }
 
-func (this ListOptions) Mode() (mode ListOptionsMode, err error) {
-	if this.TextSearch != "" {
-		mode = ListOptionsModeTextSearch
+// SetFilterFromUrlQuery reads the json encoded query-parameter 'selection'
+func (this *ListOptions) SetFilterFromUrlQuery(queryParams url.Values) (err error) {
+	selection := queryParams.Get("selection")
+	if selection == "" {
+		return nil
 	}
+	this.Filter = &Selection{}
+	err = json.Unmarshal([]byte(selection), this.Filter)
+	if err != nil {
+		return fmt.Errorf("%w: invalid selection json: %v", ErrBadRequest, err.Error())
+	}
+	return nil
+}
+
+// GetSelection combines Selection, Expression and Filter to one Selection; returns nil if none of them is set
+func (this ListOptions) GetSelection() (result *Selection, err error) {
+	selections := []Selection{}
 	if this.Selection != nil {
-		if mode != "" {
-			return mode, fmt.Errorf("%w: the ListOptions '%v' and 'Selection' may not be combined", ErrBadRequest, mode)
+		feature := this.Selection.Feature
+		if !strings.HasPrefix(feature, "features.") && !strings.HasPrefix(feature, "annotations.") {
+			feature = "features." + feature
 		}
-		mode = ListOptionsModeSelection
+		selections = append(selections, Selection{Condition: ConditionConfig{
+			Feature:   feature,
+			Operation: QueryEqualOperation,
+			Value:     this.Selection.Value,
+		}})
 	}
-	if len(this.ListIds) > 0 {
-		if mode != "" {
-			return mode, fmt.Errorf("%w: the ListOptions '%v' and 'ListIds' may not be combined", ErrBadRequest, mode)
+	if this.Expression != "" {
+		expression, err := ParseSelectionExpression(this.Expression)
+		if err != nil {
+			return nil, err
 		}
-		mode = ListOptionsModeListIds
+		selections = append(selections, expression)
+	}
+	if this.Filter != nil {
+		selections = append(selections, *this.Filter)
 	}
-	if mode == "" {
-		mode = ListOptionsModeDefault
+	switch len(selections) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &selections[0], nil
+	default:
+		return &Selection{And: selections}, nil
 	}
-	return mode, nil
 }
 
+func (this ListOptions) Validate() error {
+	err := this.ValidateSearchOptions()
+	if err != nil {
+		return err
+	}
+	return this.QueryListCommons.Validate()
+}
+
+// ValidateSearchOptions checks that search options are only used in combination with TextSearch
+func (this ListOptions) ValidateSearchOptions() error {
+	if this.TextSearch == "" && (this.Highlight || this.SearchMode != "" || this.Fuzziness != "" || len(this.SearchFields) > 0 || this.DidYouMean) {
+		return fmt.Errorf("%w: 'highlight', 'search_mode', 'fuzziness', 'search_fields' and 'did_you_mean' need a text search", ErrBadRequest)
+	}
+	return ValidateSearchMode(this.SearchMode)
+}
+
+// Mode returns the most selective option of the request
+//
+// Deprecated: all options may be combined, so the mode no longer describes how a request is executed; err is always nil
+func (this ListOptions) Mode() (mode ListOptionsMode, err error) {
+	switch {
+	case len(this.ListIds) > 0:
+		return ListOptionsModeListIds, nil
+	case this.TextSearch != "":
+		return ListOptionsModeTextSearch, nil
+	case this.Selection != nil || this.Expression != "" || this.Filter != nil:
+		return ListOptionsModeSelection, nil
+	default:
+		return ListOptionsModeDefault, nil
+	}
+}
+
+// Deprecated: see ListOptions.Mode
 type ListOptionsMode = string
 
-const ListOptionsModeTextSearch = "TextSearch"
-const ListOptionsModeSelection = "Selection"
-const ListOptionsModeListIds = "ListIds"
-const ListOptionsModeDefault = "Default"
+// Deprecated: see ListOptions.Mode
+const (
+	ListOptionsModeTextSearch = "TextSearch"
+	ListOptionsModeSelection  = "Selection"
+	ListOptionsModeListIds    = "ListIds"
+	ListOptionsModeDefault    = "Default"
+)
+
+// WithTotal is the result of list requests with with_total or with_cursor
+// Total is only guaranteed to be exact if with_total is set
+type WithTotal struct {
+	Total       int64       `json:"total"`
+	Result      interface{} `json:"result"`
+	NextCursor  string      `json:"next_cursor,omitempty"`
+	Suggestions []string    `json:"suggestions,omitempty"` //spelling suggestions of a text search without hits (see QueryFind.DidYouMean)
+}
+
+// TotalRequest is the payload of POST /v3/total/:resource, for selections that are too long for a url
+type TotalRequest struct {
+	Rights       string     `json:"rights,omitempty"` //default 'r'
+	Ids          []string   `json:"ids,omitempty"`
+	Search       string     `json:"search,omitempty"`
+	SearchMode   SearchMode `json:"search_mode,omitempty"`
+	Fuzziness    string     `json:"fuzziness,omitempty"`
+	SearchFields []string   `json:"search_fields,omitempty"`
+	Q            string     `json:"q,omitempty"` //filter expression, see ParseSelectionExpression
+	Filter       *Selection `json:"filter,omitempty"`
+}
+
+func (this TotalRequest) ToListOptions() ListOptions {
+	return ListOptions{
+		QueryListCommons: QueryListCommons{Rights: this.Rights},
+		ListIds:          this.Ids,
+		TextSearch:       this.Search,
+		SearchMode:       this.SearchMode,
+		Fuzziness:        this.Fuzziness,
+		SearchFields:     this.SearchFields,
+		Expression:       this.Q,
+		Filter:           this.Filter,
+	}
+}
@@ -308,6 +308,103 @@ const PermissionMapping = `{
 	"feature_search": {"type": "text", "analyzer": "custom_analyzer", "search_analyzer": "custom_search_analyzer"}
 }`
 
+// FeatureSearchSubField is added as multi-field to every field with 'copy_to': 'feature_search'
+// it is analyzed like feature_search, to allow text searches that are restricted to single fields
+const FeatureSearchSubField = "search"
+
+// FeatureSpellSubField is added as multi-field to every field with 'copy_to': 'feature_search'
+// it is analyzed without edge_ngram, so that its terms are complete words, which are used for spelling suggestions
+const FeatureSpellSubField = "spell"
+
+func CopiesToFeatureSearch(copyTo interface{}) bool {
+	switch v := copyTo.(type) {
+	case string:
+		return v == "feature_search"
+	case []interface{}:
+		for _, element := range v {
+			if element == "feature_search" {
+				return true
+			}
+		}
+	case []string:
+		for _, element := range v {
+			if element == "feature_search" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withFeatureSearchSubFields returns a copy of properties, where every field that is copied to feature_search has the FeatureSearchSubField
+func withFeatureSearchSubFields(properties map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, value := range properties {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			result[name] = value
+			continue
+		}
+		fieldCopy := map[string]interface{}{}
+		for key, value := range field {
+			fieldCopy[key] = value
+		}
+		if sub, ok := field["properties"].(map[string]interface{}); ok {
+			fieldCopy["properties"] = withFeatureSearchSubFields(sub)
+		}
+		if CopiesToFeatureSearch(field["copy_to"]) {
+			fields := map[string]interface{}{}
+			if existing, ok := field["fields"].(map[string]interface{}); ok {
+				for key, value := range existing {
+					fields[key] = value
+				}
+			}
+			if _, exists := fields[FeatureSearchSubField]; !exists {
+				fields[FeatureSearchSubField] = map[string]interface{}{"type": "text", "analyzer": "custom_analyzer", "search_analyzer": "custom_search_analyzer"}
+			}
+			if _, exists := fields[FeatureSpellSubField]; !exists {
+				fields[FeatureSpellSubField] = map[string]interface{}{"type": "text", "analyzer": "custom_search_analyzer"}
+			}
+			fieldCopy["fields"] = fields
+		}
+		result[name] = fieldCopy
+	}
+	return result
+}
+
+// SuggestSubField is added as multi-field to every feature with 'Suggest' in the resource config
+// it is analyzed with the autocomplete_filter edge_ngram, to match prefixes of words while typing
+const SuggestSubField = "suggest"
+
+// withSuggestSubFields returns a copy of properties, where every feature of suggestFeatures has the SuggestSubField
+// features without mapping are mapped as keyword
+func withSuggestSubFields(properties map[string]interface{}, suggestFeatures []string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, value := range properties {
+		result[name] = value
+	}
+	for _, name := range suggestFeatures {
+		field, ok := result[name].(map[string]interface{})
+		if !ok {
+			field = map[string]interface{}{"type": "keyword"}
+		}
+		fieldCopy := map[string]interface{}{}
+		for key, value := range field {
+			fieldCopy[key] = value
+		}
+		fields := map[string]interface{}{}
+		if existing, ok := field["fields"].(map[string]interface{}); ok {
+			for key, value := range existing {
+				fields[key] = value
+			}
+		}
+		fields[SuggestSubField] = map[string]interface{}{"type": "text", "analyzer": "custom_analyzer", "search_analyzer": "custom_search_analyzer"}
+		fieldCopy["fields"] = fields
+		result[name] = fieldCopy
+	}
+	return result
+}
+
 func CreateMapping(config configuration.Config, kind string) (result map[string]interface{}, err error) {
 	mapping := map[string]interface{}{}
 	err = json.Unmarshal([]byte(PermissionMapping), &mapping)
@@ -318,15 +415,24 @@ func CreateMapping(config configuration.Config, kind string) (result map[string]
 	if typeMappings, ok := config.IndexTypeMapping[kind]; ok {
 		if featureMappings, ok := typeMappings["features"]; ok {
 			mapping["features"] = map[string]interface{}{
-				"properties": featureMappings,
+				"properties": withFeatureSearchSubFields(featureMappings),
 			}
 		}
 		if annotationMappings, ok := typeMappings["annotations"]; ok {
 			mapping["annotations"] = map[string]interface{}{
-				"properties": annotationMappings,
+				"properties": withFeatureSearchSubFields(annotationMappings),
 			}
 		}
 	}
+	if suggestFeatures := config.Resources[kind].SuggestFeatures(); len(suggestFeatures) > 0 {
+		properties := map[string]interface{}{}
+		if features, ok := mapping["features"].(map[string]interface{}); ok {
+			properties, _ = features["properties"].(map[string]interface{})
+		}
+		mapping["features"] = map[string]interface{}{
+			"properties": withSuggestSubFields(properties, suggestFeatures),
+		}
+	}
 	result = map[string]interface{}{
 		"mappings": map[string]interface{}{
 			"properties": mapping,
@@ -16,6 +16,8 @@
 
 package model
 
+import "encoding/json"
+
 type SearchResult[T any] struct {
 	Took     int  `json:"took"`
 	TimedOut bool `json:"timed_out"`
@@ -25,7 +27,8 @@ type SearchResult[T any] struct {
 		Skipped    int `json:"skipped"`
 		Failed     int `json:"failed"`
 	} `json:"_shards"`
-	Hits Hits[T] `json:"hits"`
+	Hits  Hits[T] `json:"hits"`
+	PitId string  `json:"pit_id,omitempty"`
 }
 
 // AggregationResult should be used if only one kind of aggregation is requested
@@ -63,11 +66,14 @@ type Total struct {
 }
 
 type Hit[T any] struct {
-	Index  string        `json:"_index"`
-	Id     string        `json:"_id"`
-	Score  interface{}   `json:"_score"`
-	Source T             `json:"_source"`
-	Sort   []interface{} `json:"sort"`
+	Index  string            `json:"_index"`
+	Id     string            `json:"_id"`
+	Score  interface{}       `json:"_score"`
+	Source T                 `json:"_source"`
+	Sort   []json.RawMessage `json:"sort"` //raw, because long values (e.g. the Long.MAX_VALUE of missing fields) do not fit into float64
+
+	Highlight      map[string][]string `json:"highlight,omitempty"`
+	MatchedQueries []string            `json:"matched_queries,omitempty"`
 }
 
 type AliasMapping = map[AliasName]AliasWrapper
@@ -132,3 +138,17 @@ type OpenSearchGetResult struct {
 	Found       bool        `json:"found"`
 	Source      interface{} `json:"_source"`
 }
+
+type MsearchResult[T any] struct {
+	Took      int                  `json:"took"`
+	Responses []MsearchResponse[T] `json:"responses"`
+}
+
+// MsearchResponse is the result of one search of a msearch request
+// failed searches have an Error and a Status > 299
+type MsearchResponse[T any] struct {
+	SearchResult[T]
+	Aggregations json.RawMessage `json:"aggregations,omitempty"`
+	Status       int             `json:"status"`
+	Error        interface{}     `json:"error,omitempty"`
+}
@@ -30,11 +30,98 @@ type QueryMessage struct {
 	CheckIds           *QueryCheckIds `json:"check_ids"`
 	TermAggregate      *string        `json:"term_aggregate"`
 	TermAggregateLimit int            `json:"term_aggregate_limit"`
+
+	// Aggregations are computed over all resources the user may read; the result is a map[string]AggregationValue
+	// in combination with Find, the aggregations are computed over the resources matching Find and the result is a FindWithFacets
+	Aggregations map[string]Aggregation `json:"aggregations,omitempty"`
+
+	// AggregationScope restricts TermAggregate and Aggregations to the resources matching a search and filter
+	AggregationScope *AggregationScope `json:"aggregation_scope,omitempty"`
+}
+
+// AggregationScope restricts aggregations like QueryFind restricts its results
+type AggregationScope struct {
+	Search       string                 `json:"search,omitempty"`
+	Filter       *Selection             `json:"filter,omitempty"`
+	Params       map[string]interface{} `json:"params,omitempty"`
+	SearchMode   SearchMode             `json:"search_mode,omitempty"`
+	Fuzziness    string                 `json:"fuzziness,omitempty"`
+	SearchFields []string               `json:"search_fields,omitempty"`
+}
+
+// ToFind returns a QueryFind with the search and filter of the scope
+func (this AggregationScope) ToFind(rights string) QueryFind {
+	return QueryFind{
+		QueryListCommons: QueryListCommons{Rights: rights},
+		Search:           this.Search,
+		Filter:           this.Filter,
+		Params:           this.Params,
+		SearchMode:       this.SearchMode,
+		Fuzziness:        this.Fuzziness,
+		SearchFields:     this.SearchFields,
+	}
+}
+
+// ValidateAggregations checks Aggregations and their combination with AggregationScope
+func (this QueryMessage) ValidateAggregations() error {
+	if this.AggregationScope != nil {
+		if this.TermAggregate == nil && this.Aggregations == nil {
+			return fmt.Errorf("%w: 'aggregation_scope' needs 'term_aggregate' or 'aggregations'", ErrBadRequest)
+		}
+		if this.Find != nil {
+			return fmt.Errorf("%w: 'aggregation_scope' may not be combined with 'find'; the aggregations use the search and filter of 'find'", ErrBadRequest)
+		}
+		err := this.AggregationScope.ToFind("r").ValidateSearchOptions()
+		if err != nil {
+			return err
+		}
+	}
+	return ValidateAggregations(this.Aggregations)
+}
+
+// FindWithFacets is the result of a QueryMessage with Find and Aggregations
+type FindWithFacets struct {
+	Total       int64                       `json:"total"`
+	Result      interface{}                 `json:"result"`
+	NextCursor  string                      `json:"next_cursor,omitempty"`
+	Facets      map[string]AggregationValue `json:"facets"`
+	Suggestions []string                    `json:"suggestions,omitempty"`
+}
+
+// QueryBatchResult is the result of one QueryMessage of a batch request
+// Status is the http status code the QueryMessage would get as single request
+type QueryBatchResult struct {
+	Status int         `json:"status"`
+	Result interface{} `json:"result"`
+	Error  string      `json:"error,omitempty"`
 }
 type QueryFind struct {
 	QueryListCommons
-	Search string     `json:"search"`
-	Filter *Selection `json:"filter"`
+	Search string                 `json:"search"`
+	Filter *Selection             `json:"filter"`
+	Params map[string]interface{} `json:"params,omitempty"` //values for condition refs like 'param.<name>'
+
+	// Highlight adds a 'highlight' object to each result, with fragments of the fields that matched Search
+	Highlight bool `json:"highlight,omitempty"`
+
+	// SearchMode defines how Search is matched; defaults to the search_mode of the resource config
+	SearchMode SearchMode `json:"search_mode,omitempty"`
+	// Fuzziness is used by SearchModeFuzzy (e.g. "AUTO", "1" or "2"); defaults to the fuzziness of the resource config
+	Fuzziness string `json:"fuzziness,omitempty"`
+	// SearchFields restricts Search to these fields, which need 'copy_to': 'feature_search' in the index_type_mapping
+	// fields may be given without the 'features.' prefix and with a boost (e.g. ["name^3", "description"])
+	SearchFields []string `json:"search_fields,omitempty"`
+	// DidYouMean adds spelling 'suggestions' to the result if Search has no hits; the result is always a WithTotal
+	// the suggestions are derived only from resources with the requested rights
+	DidYouMean bool `json:"did_you_mean,omitempty"`
+}
+
+// ValidateSearchOptions checks that search options are only used in combination with Search
+func (this QueryFind) ValidateSearchOptions() error {
+	if this.Search == "" && (this.Highlight || this.SearchMode != "" || this.Fuzziness != "" || len(this.SearchFields) > 0 || this.DidYouMean) {
+		return fmt.Errorf("%w: 'highlight', 'search_mode', 'fuzziness', 'search_fields' and 'did_you_mean' need a search", ErrBadRequest)
+	}
+	return ValidateSearchMode(this.SearchMode)
 }
 
 type QueryListIds struct {
@@ -55,22 +142,65 @@ type QueryListCommons struct {
 	// this field must be used if offset + limit would exceed 10000
 	// when set the results begin after the referenced id and sort value
 	// the sort value is used to efficiently locate the start point
+	// alternatively After.Cursor may be set to the next_cursor of a previous response, which works with any sort
 	After *ListAfter `json:"after"`
 
 	Rights   string `json:"rights"`
 	SortBy   string `json:"sort_by"`
 	SortDesc bool   `json:"sort_desc"`
 
+	// Sort allows sorting by multiple fields, each with its own direction
+	// if set, SortBy and SortDesc are ignored
+	Sort []SortField `json:"sort,omitempty"`
+
 	// AddIdModifier is used to modify the resource id in the result
 	// possible modifiers can be found and configured in the configuration.json under result_modifiers
 	// example value: url.Values{"service_group_selection": {"a8ee3b1c-4cda-4f0d-9f55-4ef4882ce0af"}}
 	AddIdModifier url.Values `json:"add_id_modifier,omitempty"`
 
 	WithTotal bool `json:"with_total"`
+
+	// WithCursor requests a next_cursor in the response, which may be used as After.Cursor to request the next page
+	WithCursor bool `json:"with_cursor,omitempty"`
+
+	// IncludeFields and ExcludeFields limit the features and annotations in the result (e.g. ["name", "annotations.connected"])
+	// fields without 'annotations.' prefix are features; wildcards like "svg*" are allowed
+	// id, creator, permissions and shared are always part of the result
+	IncludeFields []string `json:"include_fields,omitempty"`
+	ExcludeFields []string `json:"exclude_fields,omitempty"`
+
+	// Consistent pages over a point in time snapshot of the index, so that concurrent writes do not skip or duplicate elements
+	// the snapshot is referenced by the next_cursor; requires WithCursor
+	Consistent bool `json:"consistent,omitempty"`
 }
 
 type ListAfter struct {
-	Id string `json:"id"`
+	Id     string `json:"id"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+const SortMissingFirst = "first"
+const SortMissingLast = "last"
+
+// SortByScore sorts by relevance of a text search, best matches first
+// it is the default sort of text searches without explicit sort
+const SortByScore = "_score"
+
+type SortField struct {
+	Field   string `json:"field"`
+	Desc    bool   `json:"desc"`
+	Missing string `json:"missing,omitempty"` //SortMissingFirst or SortMissingLast; default SortMissingLast
+}
+
+// GetSort returns Sort or, if Sort is empty, the sort described by SortBy and SortDesc
+func (this QueryListCommons) GetSort() []SortField {
+	if len(this.Sort) > 0 {
+		return this.Sort
+	}
+	if this.SortBy == "" {
+		return nil
+	}
+	return []SortField{{Field: this.SortBy, Desc: this.SortDesc}}
 }
 
 func (this QueryListCommons) Validate() error {
@@ -83,18 +213,38 @@ func (this QueryListCommons) Validate() error {
 	if this.Limit+this.Offset > 10000 {
 		return fmt.Errorf("%w: limit + offset may not be bigger than 10000. pleas use after.id and after.sort_field_value", ErrBadRequest)
 	}
-	if this.After != nil {
-		if this.SortBy != "id" && this.SortBy != "resource" {
-			return fmt.Errorf("%w: sort_by should be 'id' or 'resource' if 'after' is used", ErrBadRequest)
+	for _, sort := range this.Sort {
+		if sort.Field == "" {
+			return fmt.Errorf("%w: sort entries need a field", ErrBadRequest)
 		}
+		if sort.Missing != "" && sort.Missing != SortMissingFirst && sort.Missing != SortMissingLast {
+			return fmt.Errorf("%w: sort.missing should be '%v' or '%v'", ErrBadRequest, SortMissingFirst, SortMissingLast)
+		}
+	}
+	if this.Consistent {
+		if !this.WithCursor {
+			return fmt.Errorf("%w: 'consistent' needs 'with_cursor'", ErrBadRequest)
+		}
+		if this.After != nil && this.After.Id != "" {
+			return fmt.Errorf("%w: 'consistent' may not be combined with 'after.id'", ErrBadRequest)
+		}
+	}
+	if this.After != nil {
 		if this.Offset != 0 {
 			return fmt.Errorf("%w: 'offset' should be 0 if 'after' is used", ErrBadRequest)
 		}
-		if this.After.Id == "" {
-			return fmt.Errorf("%w: 'after' needs id value", ErrBadRequest)
+		if this.After.Cursor != "" {
+			if this.After.Id != "" {
+				return fmt.Errorf("%w: 'after.id' and 'after.cursor' may not be combined", ErrBadRequest)
+			}
+			return nil
 		}
-		if this.SortBy == "" {
-			return fmt.Errorf("%w: 'after' needs set sort_by value", ErrBadRequest)
+		sort := this.GetSort()
+		if len(sort) != 1 || (sort[0].Field != "id" && sort[0].Field != "resource") {
+			return fmt.Errorf("%w: sort_by should be 'id' or 'resource' if 'after.id' is used", ErrBadRequest)
+		}
+		if this.After.Id == "" {
+			return fmt.Errorf("%w: 'after' needs id or cursor value", ErrBadRequest)
 		}
 	}
 	return nil
@@ -108,7 +258,9 @@ func (this QueryListCommons) QueryValues() (result url.Values) {
 	if this.Offset > 0 {
 		result["offset"] = []string{strconv.Itoa(this.Offset)}
 	}
-	if this.SortBy != "" {
+	if len(this.Sort) > 0 {
+		result["sort"] = []string{FormatSortParam(this.Sort)}
+	} else if this.SortBy != "" {
 		sort := this.SortBy
 		if this.SortDesc {
 			sort = sort + ".desc"
@@ -121,6 +273,21 @@ func (this QueryListCommons) QueryValues() (result url.Values) {
 	if this.After != nil && this.After.Id != "" {
 		result["after.id"] = []string{this.After.Id}
 	}
+	if this.After != nil && this.After.Cursor != "" {
+		result["after.cursor"] = []string{this.After.Cursor}
+	}
+	if this.WithCursor {
+		result["with_cursor"] = []string{"true"}
+	}
+	if this.Consistent {
+		result["consistent"] = []string{"true"}
+	}
+	if len(this.IncludeFields) > 0 {
+		result["include_fields"] = []string{strings.Join(this.IncludeFields, ",")}
+	}
+	if len(this.ExcludeFields) > 0 {
+		result["exclude_fields"] = []string{strings.Join(this.ExcludeFields, ",")}
+	}
 	if len(this.AddIdModifier) > 0 {
 		result["add_id_modifier"] = []string{this.AddIdModifier.Encode()}
 	}
@@ -144,15 +311,21 @@ func GetQueryListCommonsFromUrlQuery(queryParams url.Values) (result QueryListCo
 	}
 
 	after := ListAfter{
-		Id: queryParams.Get("after.id"),
+		Id:     queryParams.Get("after.id"),
+		Cursor: queryParams.Get("after.cursor"),
 	}
-	if after.Id != "" {
+	if after.Id != "" || after.Cursor != "" {
 		result.After = &after
 	}
 
 	result.Rights = right
-	result.SortBy = strings.TrimSuffix(strings.TrimSuffix(sort, ".desc"), ".asc")
-	result.SortDesc = strings.HasSuffix(sort, ".desc")
+	sortFields := ParseSortParam(sort)
+	if len(sortFields) == 1 && sortFields[0].Missing == "" {
+		result.SortBy = sortFields[0].Field
+		result.SortDesc = sortFields[0].Desc
+	} else {
+		result.Sort = sortFields
+	}
 
 	result.Limit, err = strconv.Atoi(limit)
 	if err != nil {
@@ -163,6 +336,31 @@ func GetQueryListCommonsFromUrlQuery(queryParams url.Values) (result QueryListCo
 		return
 	}
 
+	if withTotal := queryParams.Get("with_total"); withTotal != "" {
+		result.WithTotal, err = strconv.ParseBool(withTotal)
+		if err != nil {
+			return result, fmt.Errorf("%w: invalid with_total value: %v", ErrBadRequest, err.Error())
+		}
+	}
+	if withCursor := queryParams.Get("with_cursor"); withCursor != "" {
+		result.WithCursor, err = strconv.ParseBool(withCursor)
+		if err != nil {
+			return result, fmt.Errorf("%w: invalid with_cursor value: %v", ErrBadRequest, err.Error())
+		}
+	}
+	if includeFields := queryParams.Get("include_fields"); includeFields != "" {
+		result.IncludeFields = strings.Split(includeFields, ",")
+	}
+	if excludeFields := queryParams.Get("exclude_fields"); excludeFields != "" {
+		result.ExcludeFields = strings.Split(excludeFields, ",")
+	}
+	if consistent := queryParams.Get("consistent"); consistent != "" {
+		result.Consistent, err = strconv.ParseBool(consistent)
+		if err != nil {
+			return result, fmt.Errorf("%w: invalid consistent value: %v", ErrBadRequest, err.Error())
+		}
+	}
+
 	addIdModifier := queryParams.Get("add_id_modifier")
 	if addIdModifier != "" {
 		result.AddIdModifier, err = url.ParseQuery(addIdModifier)
@@ -199,3 +397,44 @@ func GetQueryListCommonsFromStrings(limit string, offset string, sortBy string,
 	err = result.Validate()
 	return
 }
+
+// ParseSortParam parses a comma separated list of sort fields
+// each field may have the suffix '.asc' or '.desc', optionally followed by '.missing_first' or '.missing_last'
+// example: 'annotations.connected.desc,name'
+func ParseSortParam(sort string) (result []SortField) {
+	for _, element := range strings.Split(sort, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		field := SortField{}
+		if strings.HasSuffix(element, ".missing_first") {
+			field.Missing = SortMissingFirst
+			element = strings.TrimSuffix(element, ".missing_first")
+		}
+		if strings.HasSuffix(element, ".missing_last") {
+			field.Missing = SortMissingLast
+			element = strings.TrimSuffix(element, ".missing_last")
+		}
+		field.Desc = strings.HasSuffix(element, ".desc")
+		field.Field = strings.TrimSuffix(strings.TrimSuffix(element, ".desc"), ".asc")
+		result = append(result, field)
+	}
+	return result
+}
+
+// FormatSortParam is the inverse of ParseSortParam
+func FormatSortParam(sort []SortField) string {
+	elements := []string{}
+	for _, field := range sort {
+		element := field.Field
+		if field.Desc {
+			element = element + ".desc"
+		}
+		if field.Missing != "" {
+			element = element + ".missing_" + field.Missing
+		}
+		elements = append(elements, element)
+	}
+	return strings.Join(elements, ",")
+}
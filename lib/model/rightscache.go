@@ -0,0 +1,30 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// RightsCacheStats describes the in-process cache of resource rights used by access checks (see GET /v3/administrate/rights-cache)
+type RightsCacheStats struct {
+	Enabled       bool    `json:"enabled"`
+	Size          int     `json:"size"`
+	MaxSize       int     `json:"max_size"`
+	Ttl           string  `json:"ttl"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRate       float64 `json:"hit_rate"` //hits / (hits + misses); 0 without lookups
+	Evictions     uint64  `json:"evictions"`
+	Invalidations uint64  `json:"invalidations"`
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// SavedQueryRequest is the payload of POST /v3/saved-queries/:resource/:name
+// the saved query is a QueryFind template from the 'saved_queries' config
+type SavedQueryRequest struct {
+	Params map[string]interface{} `json:"params,omitempty"` //values for condition refs like 'param.<name>'; override params of the template
+
+	// optional paging, which overrides the paging of the template
+	Limit      int        `json:"limit,omitempty"`
+	Offset     int        `json:"offset,omitempty"`
+	After      *ListAfter `json:"after,omitempty"`
+	WithTotal  bool       `json:"with_total,omitempty"`
+	WithCursor bool       `json:"with_cursor,omitempty"`
+}
+
+// Apply returns a copy of the template find with the params and paging of the request
+func (this SavedQueryRequest) Apply(find QueryFind) QueryFind {
+	params := map[string]interface{}{}
+	for key, value := range find.Params {
+		params[key] = value
+	}
+	for key, value := range this.Params {
+		params[key] = value
+	}
+	find.Params = params
+	if this.Limit != 0 {
+		find.Limit = this.Limit
+	}
+	if this.Offset != 0 {
+		find.Offset = this.Offset
+	}
+	if this.After != nil {
+		find.After = this.After
+	}
+	find.WithTotal = find.WithTotal || this.WithTotal
+	find.WithCursor = find.WithCursor || this.WithCursor
+	return find
+}
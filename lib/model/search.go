@@ -0,0 +1,44 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "fmt"
+
+type SearchMode = string
+
+const (
+	SearchModeDefault SearchMode = "default" //match all words as prefix; wildcard search if the text contains '*'
+	SearchModeExact   SearchMode = "exact"   //case-insensitive match of the complete value of a searchable field
+	SearchModePrefix  SearchMode = "prefix"  //case-insensitive match of the start of a searchable field
+	SearchModeFuzzy   SearchMode = "fuzzy"   //like default, but tolerates typos
+	SearchModePhrase  SearchMode = "phrase"  //all words in the given order
+)
+
+var SearchModes = []SearchMode{SearchModeDefault, SearchModeExact, SearchModePrefix, SearchModeFuzzy, SearchModePhrase}
+
+// ValidateSearchMode accepts all SearchModes and the empty string, which selects the configured default
+func ValidateSearchMode(mode SearchMode) error {
+	if mode == "" {
+		return nil
+	}
+	for _, m := range SearchModes {
+		if m == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown search_mode '%v' (expected one of %v)", ErrBadRequest, mode, SearchModes)
+}
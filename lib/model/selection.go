@@ -19,9 +19,19 @@ package model
 type QueryOperationType string
 
 const (
-	QueryEqualOperation             QueryOperationType = "=="
-	QueryUnequalOperation           QueryOperationType = "!="
-	QueryAnyValueInFeatureOperation QueryOperationType = "any_value_in_feature"
+	QueryEqualOperation                QueryOperationType = "=="
+	QueryUnequalOperation              QueryOperationType = "!="
+	QueryAnyValueInFeatureOperation    QueryOperationType = "any_value_in_feature"
+	QueryGreaterOperation              QueryOperationType = ">"
+	QueryGreaterEqualOperation         QueryOperationType = ">="
+	QueryLessOperation                 QueryOperationType = "<"
+	QueryLessEqualOperation            QueryOperationType = "<="
+	QueryBetweenOperation              QueryOperationType = "between"
+	QueryPrefixOperation               QueryOperationType = "prefix"
+	QueryWildcardOperation             QueryOperationType = "wildcard"
+	QueryRegexpOperation               QueryOperationType = "regexp"
+	QueryEqualCaseInsensitiveOperation QueryOperationType = "==ci"
+	QueryInResourceQueryOperation      QueryOperationType = "in_resource_query"
 )
 
 type ConditionConfig struct {
@@ -32,8 +42,25 @@ type ConditionConfig struct {
 }
 
 type Selection struct {
-	And       []Selection     `json:"and"`
-	Or        []Selection     `json:"or"`
-	Not       *Selection      `json:"not"`
-	Condition ConditionConfig `json:"condition"`
+	And       []Selection      `json:"and"`
+	Or        []Selection      `json:"or"`
+	Not       *Selection       `json:"not"`
+	Nested    *NestedSelection `json:"nested"`
+	Condition ConditionConfig  `json:"condition"`
+}
+
+// NestedSelection applies Selection to each element of the list at Path individually
+// Path has to be mapped with the type 'nested' in the index_type_mapping (e.g. 'features.attributes')
+// features in Selection use the full path (e.g. 'features.attributes.key')
+type NestedSelection struct {
+	Path      string    `json:"path"`
+	Selection Selection `json:"selection"`
+}
+
+// ResourceQuery is the value of a condition with the operation 'in_resource_query'
+// the condition matches if the feature contains the id of a Resource element, which is readable with Rights and matches Filter
+type ResourceQuery struct {
+	Resource string     `json:"resource"`
+	Rights   string     `json:"rights"` //default "r"
+	Filter   *Selection `json:"filter"`
 }
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type SuggestOptions struct {
+	Query  string
+	Limit  int    //default 10
+	Field  string //optional; restricts suggestions to one feature with 'Suggest' in the resource config
+	Rights string //default "r"
+}
+
+type Suggestion struct {
+	Id   string `json:"id"`
+	Text string `json:"text"`
+}
+
+func (this SuggestOptions) QueryValues() url.Values {
+	result := url.Values{"q": {this.Query}}
+	if this.Limit != 0 {
+		result["limit"] = []string{strconv.Itoa(this.Limit)}
+	}
+	if this.Field != "" {
+		result["field"] = []string{this.Field}
+	}
+	if this.Rights != "" {
+		result["rights"] = []string{this.Rights}
+	}
+	return result
+}
+
+func GetSuggestOptionsFromUrlQuery(queryParams url.Values) (result SuggestOptions, err error) {
+	result.Query = queryParams.Get("q")
+	result.Field = queryParams.Get("field")
+	result.Rights = queryParams.Get("rights")
+	if limit := queryParams.Get("limit"); limit != "" {
+		result.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return result, fmt.Errorf("%w: invalid limit: %v", ErrBadRequest, err.Error())
+		}
+	}
+	return result, nil
+}
+
+func (this SuggestOptions) Validate() error {
+	if this.Query == "" {
+		return fmt.Errorf("%w: missing query parameter 'q'", ErrBadRequest)
+	}
+	if this.Limit < 0 || this.Limit > 100 {
+		return fmt.Errorf("%w: limit should be between 0 and 100", ErrBadRequest)
+	}
+	return nil
+}
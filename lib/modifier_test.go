@@ -145,6 +145,9 @@ func TestResultModifiers(t *testing.T) {
 		getTestDeviceResultWithDeviceTypeIdAndName(dIdWithModify, dNameModify, dtIdModified),
 	}))
 
+	t.Run("total ids both", testRequest(config, "GET", "/v3/total/devices?ids="+url.QueryEscape(dId)+","+url.QueryEscape(dIdWithModify), nil, 200, 2))
+	t.Run("total ids modified", testRequest(config, "GET", "/v3/total/devices?ids="+url.QueryEscape(dIdWithModify), nil, 200, 1))
+
 	t.Run("access true", testRequest(config, "GET", "/v3/resources/devices/"+url.PathEscape(dIdWithModify)+"/access", nil, 200, true))
 	t.Run("access true not encoded", testRequest(config, "GET", "/v3/resources/devices/"+dIdWithModify+"/access", nil, 200, true))
 
@@ -218,6 +221,29 @@ func TestResultModifiers(t *testing.T) {
 		getTestDeviceResultWithDeviceTypeIdAndName(dIdWithModify, dNameModify, dtIdModified),
 	}))
 
+	t.Run("add modify to query batch filter result", testRequest(config, "POST", "/v3/query-batch", []model.QueryMessage{{
+		Resource: "devices",
+		Find: &model.QueryFind{
+			QueryListCommons: model.QueryListCommons{
+				AddIdModifier: map[string][]string{
+					"service_group_selection": {serviceGroupKey},
+				},
+			},
+			Filter: &model.Selection{
+				Condition: model.ConditionConfig{
+					Feature:   "features.device_type_id",
+					Operation: model.QueryEqualOperation,
+					Value:     dtId,
+				},
+			},
+		},
+	}}, 200, []model.QueryBatchResult{{
+		Status: 200,
+		Result: []map[string]interface{}{
+			getTestDeviceResultWithDeviceTypeIdAndName(dIdWithModify, dNameModify, dtIdModified),
+		},
+	}}))
+
 	t.Run("add modify to filter result", testRequest(config, "GET", "/v3/resources/devices?filter="+url.PathEscape("device_type_id:"+dtId)+"&add_id_modifier="+url.QueryEscape(modifier.EncodeModifierParameter(map[string][]string{"service_group_selection": {serviceGroupKey}})),
 		nil,
 		200,
@@ -122,7 +122,8 @@ func CreateIndex(kind string, client *opensearch.Client, ctx context.Context, co
 	} else if config.TryMappingUpdateOnStartup {
 		err = updateIndexMappingWithoutReindex(kind, client, ctx, mapping)
 		if err != nil {
-			log.Println("WARNING: unable to update index mapping", err)
+			//incompatible changes (e.g. object to nested) need a reindex by the 'update-indexes' command
+			log.Println("WARNING: unable to update index mapping; use the 'update-indexes' command for incompatible mapping changes", err)
 			return nil //ignore error
 		}
 	}
@@ -293,3 +294,31 @@ func indicesWithAlias(mapping model.AliasMapping, alias string) (result []string
 	}
 	return result
 }
+
+// GetIndexProperties returns the mapping properties of the index that is referenced by the alias kind
+func GetIndexProperties(client *opensearch.Client, ctx context.Context, kind string) (properties map[string]interface{}, err error) {
+	resp, err := client.Indices.GetMapping(client.Indices.GetMapping.WithIndex(kind), client.Indices.GetMapping.WithContext(ctx))
+	if err != nil {
+		return properties, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return properties, errors.New(resp.String())
+	}
+	indexes := map[string]struct {
+		Mappings struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"mappings"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&indexes)
+	if err != nil {
+		return properties, err
+	}
+	if len(indexes) != 1 {
+		return properties, fmt.Errorf("unexpected mapping result for %v: %v indexes", kind, len(indexes))
+	}
+	for _, index := range indexes {
+		properties = index.Mappings.Properties
+	}
+	return properties, nil
+}
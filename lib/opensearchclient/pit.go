@@ -0,0 +1,154 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opensearchclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"net/http"
+	"net/url"
+)
+
+// PitKeepAlive is the time a point in time is kept alive after its last use
+var PitKeepAlive = "1m"
+
+// the used opensearch-go version has no point in time api -> raw requests
+
+// OpenPit creates a point in time of index (or alias), which keeps the current state searchable even if documents are written concurrently
+func OpenPit(ctx context.Context, client *opensearch.Client, index string) (pitId string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search/point_in_time?keep_alive="+url.QueryEscape(PitKeepAlive), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Perform(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	result := opensearchapi.Response{StatusCode: resp.StatusCode, Body: resp.Body, Header: resp.Header}
+	if result.IsError() {
+		return "", errors.New(result.String())
+	}
+	pl := struct {
+		PitId string `json:"pit_id"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return "", err
+	}
+	return pl.PitId, nil
+}
+
+// ClosePit deletes a point in time before its keep alive ends
+// returns model.ErrNotFound if the point in time is unknown or expired
+func ClosePit(ctx context.Context, client *opensearch.Client, pitId string) (err error) {
+	body := opensearchutil.NewJSONReader(map[string]interface{}{"pit_id": []string{pitId}})
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, "/_search/point_in_time", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Perform(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	result := opensearchapi.Response{StatusCode: resp.StatusCode, Body: resp.Body, Header: resp.Header}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: point in time is expired", model.ErrNotFound)
+	}
+	if result.IsError() {
+		return errors.New(result.String())
+	}
+	return nil
+}
+
+// IterateEntries calls handler with batches of all entries of index that match query, sorted by resource id
+// the iteration uses a point in time, so that concurrent writes do not skip or duplicate entries
+// every search of a batch renews the keep_alive of the point in time; it only expires if handler blocks longer than PitKeepAlive
+// ctx cancels the iteration (e.g. if the consumer of the entries is gone); the point in time is closed in any case
+func IterateEntries(ctx context.Context, client *opensearch.Client, index string, query map[string]interface{}, batchSize int, handler func(entries []model.Entry) error) (err error) {
+	pitId, err := OpenPit(ctx, client, index)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := ClosePit(context.Background(), client, pitId)
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	var after []json.RawMessage
+	for {
+		body := map[string]interface{}{
+			"query": query,
+			"pit": map[string]interface{}{
+				"id":         pitId,
+				"keep_alive": PitKeepAlive,
+			},
+			"sort": []interface{}{map[string]interface{}{"resource": "asc"}},
+		}
+		if after != nil {
+			body["search_after"] = after
+		}
+		pl, err := searchPit(ctx, client, body, batchSize)
+		if err != nil {
+			return err
+		}
+		if pl.PitId != "" {
+			pitId = pl.PitId
+		}
+		entries := []model.Entry{}
+		for _, hit := range pl.Hits.Hits {
+			entries = append(entries, hit.Source)
+			after = hit.Sort
+		}
+		if len(entries) > 0 {
+			err = handler(entries)
+			if err != nil {
+				return err
+			}
+		}
+		if len(pl.Hits.Hits) < batchSize {
+			return nil
+		}
+	}
+}
+
+func searchPit(ctx context.Context, client *opensearch.Client, body map[string]interface{}, size int) (result model.SearchResult[model.Entry], err error) {
+	resp, err := client.Search(
+		client.Search.WithContext(ctx),
+		client.Search.WithVersion(true),
+		client.Search.WithSize(size),
+		client.Search.WithBody(opensearchutil.NewJSONReader(body)),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFieldProjection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-types"
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "foo", "description": "bar", "service": "s1"}))
+
+	time.Sleep(2 * time.Second)
+
+	check := func(queryCommons model.QueryListCommons, expectedFields []string, unexpectedFields []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			queryCommons.Limit = 10
+			queryCommons.Rights = "r"
+			result, err := q.List(testtoken, resource, model.ListOptions{QueryListCommons: queryCommons})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(result) != 1 {
+				t.Error(result)
+				return
+			}
+			for _, field := range append(expectedFields, "id", "creator", "permissions", "shared") {
+				if _, ok := result[0][field]; !ok {
+					t.Error("missing", field, result[0])
+				}
+			}
+			for _, field := range unexpectedFields {
+				if _, ok := result[0][field]; ok {
+					t.Error("unexpected", field, result[0])
+				}
+			}
+		}
+	}
+
+	t.Run("all", check(model.QueryListCommons{}, []string{"name", "description", "service"}, nil))
+	t.Run("include", check(model.QueryListCommons{IncludeFields: []string{"name"}}, []string{"name"}, []string{"description", "service"}))
+	t.Run("include wildcard", check(model.QueryListCommons{IncludeFields: []string{"features.desc*"}}, []string{"description"}, []string{"name", "service"}))
+	t.Run("exclude", check(model.QueryListCommons{ExcludeFields: []string{"description"}}, []string{"name", "service"}, []string{"description"}))
+	t.Run("ids", func(t *testing.T) {
+		result, err := q.List(testtoken, resource, model.ListOptions{
+			QueryListCommons: model.QueryListCommons{Limit: 10, Rights: "r", IncludeFields: []string{"name"}},
+			ListIds:          []string{"dt1"},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 || result[0]["name"] != "foo" || result[0]["description"] != nil || result[0]["permissions"] == nil {
+			t.Error(result)
+		}
+	})
+
+	resourceConfig := config.Resources[resource]
+	resourceConfig.DefaultExcludeFields = []string{"description"}
+	config.Resources[resource] = resourceConfig
+
+	t.Run("default exclude", check(model.QueryListCommons{}, []string{"name", "service"}, []string{"description"}))
+	t.Run("default exclude overwritten by include", check(model.QueryListCommons{IncludeFields: []string{"name", "description"}}, []string{"name", "description"}, []string{"service"}))
+}
@@ -19,6 +19,7 @@ package query
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
 	"github.com/opensearch-project/opensearch-go/opensearchutil"
@@ -29,28 +30,14 @@ func (this *Query) GetTermAggregation(tokenStr string, kind string, rights strin
 	if err != nil {
 		return result, err
 	}
-	return this.getTermAggregation(token, kind, rights, field, limit)
+	return this.getTermAggregation(token, kind, rights, nil, field, limit)
 }
 
-func (this *Query) getTermAggregation(token auth.Token, kind string, rights string, field string, limit int) (result []model.TermAggregationResultElement, err error) {
-	if limit == 0 {
-		limit = 100
-	}
+func (this *Query) getTermAggregation(token auth.Token, kind string, rights string, scope *model.AggregationScope, field string, limit int) (result []model.TermAggregationResultElement, err error) {
 	ctx := this.getTimeout()
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"filter": getRightsQuery(rights, token.GetUserId(), token.GetRoles()),
-			},
-		},
-		"aggregations": map[string]interface{}{
-			field: map[string]interface{}{
-				"terms": map[string]interface{}{
-					"field": field,
-					"size":  limit,
-				},
-			},
-		},
+	query, err := this.getTermAggregationBody(token, kind, rights, scope, field, limit)
+	if err != nil {
+		return result, err
 	}
 
 	resp, err := this.opensearchClient.Search(
@@ -72,7 +59,43 @@ func (this *Query) getTermAggregation(token auth.Token, kind string, rights stri
 	if err != nil {
 		return result, err
 	}
-	termsAggregation, found := pl.Aggregations[field]
+	return getTermAggregationResult(pl.Aggregations, field)
+}
+
+func (this *Query) getTermAggregationBody(token auth.Token, kind string, rights string, scope *model.AggregationScope, field string, limit int) (body map[string]interface{}, err error) {
+	if limit == 0 {
+		limit = 100
+	}
+	body, err = this.getAggregationQueryBody(token, kind, rights, scope)
+	if err != nil {
+		return body, err
+	}
+	body["aggregations"] = map[string]interface{}{
+		field: map[string]interface{}{
+			"terms": map[string]interface{}{
+				"field": field,
+				"size":  limit,
+			},
+		},
+	}
+	return body, nil
+}
+
+// getAggregationQueryBody returns the query for aggregations over the resources with rights, optionally restricted by scope
+func (this *Query) getAggregationQueryBody(token auth.Token, kind string, rights string, scope *model.AggregationScope) (body map[string]interface{}, err error) {
+	if scope == nil {
+		scope = &model.AggregationScope{}
+	}
+	body, err = this.getFindBody(token, kind, scope.ToFind(rights))
+	if err != nil {
+		return body, err
+	}
+	body["size"] = 0
+	return body, nil
+}
+
+func getTermAggregationResult(aggregations map[string]model.TermsAggrT, field string) (result []model.TermAggregationResultElement, err error) {
+	termsAggregation, found := aggregations[field]
 	if !found {
 		return nil, errors.New("aggregation result not found in response from database")
 	}
@@ -82,5 +105,219 @@ func (this *Query) getTermAggregation(token auth.Token, kind string, rights stri
 			Count: bucket.DocCount,
 		})
 	}
-	return result, err
+	return result, nil
+}
+
+func (this *Query) getAggregations(token auth.Token, kind string, scope *model.AggregationScope, aggregations map[string]model.Aggregation) (result map[string]model.AggregationValue, err error) {
+	ctx := this.getTimeout()
+	query, err := this.getAggregationsBody(token, kind, "r", scope, aggregations)
+	if err != nil {
+		return result, err
+	}
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(ctx),
+		this.opensearchClient.Search.WithIndex(kind),
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(query)),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := model.AggregationsResult[model.Entry, map[string]json.RawMessage]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	return getAggregationValues(aggregations, pl.Aggregations)
+}
+
+// getAggregationsBody returns a search request body, which computes aggregations over all resources with the given rights, optionally restricted by scope
+func (this *Query) getAggregationsBody(token auth.Token, kind string, rights string, scope *model.AggregationScope, aggregations map[string]model.Aggregation) (body map[string]interface{}, err error) {
+	body, err = this.getAggregationQueryBody(token, kind, rights, scope)
+	if err != nil {
+		return body, err
+	}
+	body["aggregations"] = getAggregationsRequest(aggregations)
+	return body, nil
+}
+
+const defaultAggregationMaxSize = 1000
+const defaultAggregationMaxDepth = 3
+
+// validateAggregations checks query.Aggregations with model.QueryMessage.ValidateAggregations
+// and against the limits of the config and the index_type_mapping of query.Resource
+func (this *Query) validateAggregations(query model.QueryMessage) error {
+	err := query.ValidateAggregations()
+	if err != nil {
+		return err
+	}
+	maxSize := this.config.AggregationMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultAggregationMaxSize
+	}
+	maxDepth := this.config.AggregationMaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultAggregationMaxDepth
+	}
+	return this.checkAggregationLimits(query.Resource, query.Aggregations, maxSize, maxDepth, 1)
+}
+
+func (this *Query) checkAggregationLimits(kind string, aggregations map[string]model.Aggregation, maxSize int64, maxDepth int64, depth int64) error {
+	if len(aggregations) > 0 && depth > maxDepth {
+		return fmt.Errorf("%w: aggregations may be nested at most %v levels deep", model.ErrBadRequest, maxDepth)
+	}
+	for name, aggregation := range aggregations {
+		if int64(aggregation.Size) > maxSize {
+			return fmt.Errorf("%w: size of aggregation %v may be at most %v", model.ErrBadRequest, name, maxSize)
+		}
+		field := getIndexField(aggregation.Field)
+		fieldType, found := this.getMappingType(kind, field)
+		if !found {
+			return fmt.Errorf("%w: aggregation %v needs a field with a type described in index_type_mapping (%v)", model.ErrBadRequest, name, field)
+		}
+		if !isAggregatableFieldType(aggregation.Type, fieldType) {
+			return fmt.Errorf("%w: aggregation %v of type %v is not supported for %v with type %v", model.ErrBadRequest, name, aggregation.Type, field, fieldType)
+		}
+		err := this.checkAggregationLimits(kind, aggregation.Aggregations, maxSize, maxDepth, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isAggregatableFieldType limits aggregations to fields with doc values: keyword, numeric and date fields
+// date_histogram needs a date field and stats a numeric or date field
+func isAggregatableFieldType(aggregationType model.AggregationType, fieldType string) bool {
+	isDate := fieldType == "date" || fieldType == "date_nanos"
+	switch aggregationType {
+	case model.AggregationTypeDateHistogram:
+		return isDate
+	case model.AggregationTypeStats:
+		return isDate || isNumericMappingType(fieldType)
+	default:
+		return isDate || isNumericMappingType(fieldType) || fieldType == "keyword"
+	}
+}
+
+func getAggregationsRequest(aggregations map[string]model.Aggregation) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, aggregation := range aggregations {
+		field := getIndexField(aggregation.Field)
+		var request map[string]interface{}
+		switch aggregation.Type {
+		case model.AggregationTypeTerms:
+			size := aggregation.Size
+			if size == 0 {
+				size = 100
+			}
+			request = map[string]interface{}{"terms": map[string]interface{}{"field": field, "size": size}}
+		case model.AggregationTypeDateHistogram:
+			interval := map[string]interface{}{"field": field}
+			if isCalendarInterval(aggregation.Interval) {
+				interval["calendar_interval"] = aggregation.Interval
+			} else {
+				interval["fixed_interval"] = aggregation.Interval
+			}
+			request = map[string]interface{}{"date_histogram": interval}
+		case model.AggregationTypeStats:
+			request = map[string]interface{}{"stats": map[string]interface{}{"field": field}}
+		case model.AggregationTypeCardinality:
+			request = map[string]interface{}{"cardinality": map[string]interface{}{"field": field}}
+		}
+		if len(aggregation.Aggregations) > 0 {
+			request["aggregations"] = getAggregationsRequest(aggregation.Aggregations)
+		}
+		result[name] = request
+	}
+	return result
+}
+
+func isCalendarInterval(interval string) bool {
+	switch interval {
+	case "minute", "1m", "hour", "1h", "day", "1d", "week", "1w", "month", "1M", "quarter", "1q", "year", "1y":
+		return true
+	default:
+		return false
+	}
+}
+
+// getAggregationValues translates the aggregations of an OpenSearch response to the api result
+func getAggregationValues(aggregations map[string]model.Aggregation, raw map[string]json.RawMessage) (result map[string]model.AggregationValue, err error) {
+	result = map[string]model.AggregationValue{}
+	for name, aggregation := range aggregations {
+		value, found := raw[name]
+		if !found {
+			return result, errors.New("aggregation result not found in response from database")
+		}
+		switch aggregation.Type {
+		case model.AggregationTypeTerms, model.AggregationTypeDateHistogram:
+			pl := struct {
+				Buckets []json.RawMessage `json:"buckets"`
+			}{}
+			err = json.Unmarshal(value, &pl)
+			if err != nil {
+				return result, err
+			}
+			buckets := []model.AggregationBucket{}
+			for _, rawBucket := range pl.Buckets {
+				bucket, err := getAggregationBucket(aggregation, rawBucket)
+				if err != nil {
+					return result, err
+				}
+				buckets = append(buckets, bucket)
+			}
+			result[name] = model.AggregationValue{Buckets: buckets}
+		case model.AggregationTypeStats:
+			stats := model.AggregationStats{}
+			err = json.Unmarshal(value, &stats)
+			if err != nil {
+				return result, err
+			}
+			result[name] = model.AggregationValue{Stats: &stats}
+		case model.AggregationTypeCardinality:
+			pl := struct {
+				Value int64 `json:"value"`
+			}{}
+			err = json.Unmarshal(value, &pl)
+			if err != nil {
+				return result, err
+			}
+			result[name] = model.AggregationValue{Cardinality: &pl.Value}
+		}
+	}
+	return result, nil
+}
+
+// getAggregationBucket translates a bucket of a terms or date_histogram aggregation; sub aggregations are fields of the bucket
+func getAggregationBucket(aggregation model.Aggregation, raw json.RawMessage) (result model.AggregationBucket, err error) {
+	pl := struct {
+		Key         interface{} `json:"key"`
+		KeyAsString string      `json:"key_as_string"`
+		DocCount    int64       `json:"doc_count"`
+	}{}
+	err = json.Unmarshal(raw, &pl)
+	if err != nil {
+		return result, err
+	}
+	result = model.AggregationBucket{
+		Key:         pl.Key,
+		KeyAsString: pl.KeyAsString,
+		Count:       pl.DocCount,
+	}
+	if len(aggregation.Aggregations) > 0 {
+		subAggregations := map[string]json.RawMessage{}
+		err = json.Unmarshal(raw, &subAggregations)
+		if err != nil {
+			return result, err
+		}
+		result.Aggregations, err = getAggregationValues(aggregation.Aggregations, subAggregations)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"testing"
+)
+
+func TestAggregationLimits(t *testing.T) {
+	q := &Query{config: &configuration.ConfigStruct{
+		AggregationMaxSize:  10,
+		AggregationMaxDepth: 2,
+		IndexTypeMapping: map[string]map[string]map[string]interface{}{
+			"devices": {
+				"features": {
+					"name":        map[string]interface{}{"type": "keyword"},
+					"description": map[string]interface{}{"type": "text"},
+					"count":       map[string]interface{}{"type": "long"},
+					"date":        map[string]interface{}{"type": "date"},
+				},
+			},
+		},
+	}}
+	for name, c := range map[string]struct {
+		aggregations map[string]model.Aggregation
+		valid        bool
+	}{
+		"terms": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeTerms, Field: "name", Size: 10}},
+			valid:        true,
+		},
+		"size": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeTerms, Field: "name", Size: 11}},
+		},
+		"text field": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeTerms, Field: "description"}},
+		},
+		"unknown field": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeCardinality, Field: "unknown"}},
+		},
+		"stats": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeStats, Field: "count"}},
+			valid:        true,
+		},
+		"stats of keyword": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeStats, Field: "name"}},
+		},
+		"date_histogram of number": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeDateHistogram, Field: "count", Interval: "day"}},
+		},
+		"depth": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeDateHistogram, Field: "date", Interval: "day", Aggregations: map[string]model.Aggregation{
+				"b": {Type: model.AggregationTypeTerms, Field: "name"},
+			}}},
+			valid: true,
+		},
+		"too deep": {
+			aggregations: map[string]model.Aggregation{"a": {Type: model.AggregationTypeDateHistogram, Field: "date", Interval: "day", Aggregations: map[string]model.Aggregation{
+				"b": {Type: model.AggregationTypeTerms, Field: "name", Aggregations: map[string]model.Aggregation{
+					"c": {Type: model.AggregationTypeCardinality, Field: "id"},
+				}},
+			}}},
+		},
+	} {
+		err := q.validateAggregations(model.QueryMessage{Resource: "devices", Aggregations: c.aggregations})
+		if c.valid && err != nil {
+			t.Error(name, err)
+		}
+		if !c.valid && !errors.Is(err, model.ErrBadRequest) {
+			t.Error(name, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+)
+
+// batchHandler transforms the msearch response of a batch entry to the result of Query
+type batchHandler = func(response model.MsearchResponse[model.Entry]) (result interface{}, err error)
+
+// defaultQueryBatchLimit is the max count of query messages of QueryBatch if config.QueryBatchLimit is not set
+const defaultQueryBatchLimit = 100
+
+// QueryBatch executes multiple query messages; finds and term aggregations are combined to one msearch request
+// a failing query message does not fail the batch but is returned as result with an error status
+func (this *Query) QueryBatch(tokenStr string, queries []model.QueryMessage) (result []model.QueryBatchResult, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return result, err
+	}
+	limit := this.config.QueryBatchLimit
+	if limit <= 0 {
+		limit = defaultQueryBatchLimit
+	}
+	if int64(len(queries)) > limit {
+		return result, fmt.Errorf("%w: batch contains %v query messages; at most %v are allowed", model.ErrBadRequest, len(queries), limit)
+	}
+	result = make([]model.QueryBatchResult, len(queries))
+	requests := []msearchRequest{}
+	handlers := []batchHandler{}
+	indexes := []int{}
+	for i, query := range queries {
+		request, handler, ok, err := this.getBatchMsearchRequest(token, query)
+		if err != nil {
+			result[i] = getBatchResult(nil, model.GetErrCode(err), err)
+			continue
+		}
+		if !ok {
+			queryResult, code, err := this.Query(tokenStr, query)
+			result[i] = getBatchResult(queryResult, code, err)
+			continue
+		}
+		requests = append(requests, request)
+		handlers = append(handlers, handler)
+		indexes = append(indexes, i)
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+	responses, err := this.msearchEntries(requests)
+	if err != nil {
+		for _, i := range indexes {
+			result[i] = getBatchResult(nil, http.StatusInternalServerError, err)
+		}
+		return result, nil
+	}
+	for j, response := range responses {
+		i := indexes[j]
+		if response.Error != nil || response.Status > 299 {
+			status := response.Status
+			if status < 300 {
+				status = http.StatusInternalServerError
+			}
+			msg, _ := json.Marshal(response.Error)
+			result[i] = model.QueryBatchResult{Status: status, Error: string(msg)}
+			continue
+		}
+		queryResult, err := handlers[j](response)
+		result[i] = getBatchResult(queryResult, model.GetErrCode(err), err)
+	}
+	return result, nil
+}
+
+// getBatchMsearchRequest returns the msearch request for query
+// ok is false if the query can not be part of a msearch request and has to be executed by Query
+// finds with consistent, did_you_mean or add_id_modifier need further requests and are executed by Query
+func (this *Query) getBatchMsearchRequest(token auth.Token, query model.QueryMessage) (request msearchRequest, handler batchHandler, ok bool, err error) {
+	err = this.validateAggregations(query)
+	if err != nil {
+		return request, handler, false, err
+	}
+	switch {
+	case query.Find != nil && query.ListIds == nil && query.CheckIds == nil && query.TermAggregate == nil && !query.Find.Consistent && !query.Find.DidYouMean && len(query.Find.AddIdModifier) == 0:
+		request, handler, err = this.getBatchFindRequest(token, query.Resource, *query.Find, query.Aggregations)
+		return request, handler, err == nil, err
+	case query.TermAggregate != nil && query.Find == nil && query.ListIds == nil && query.CheckIds == nil && query.Aggregations == nil:
+		field := *query.TermAggregate
+		request.Index = query.Resource
+		request.Body, err = this.getTermAggregationBody(token, query.Resource, "r", query.AggregationScope, field, query.TermAggregateLimit)
+		if err != nil {
+			return request, handler, false, err
+		}
+		handler = func(response model.MsearchResponse[model.Entry]) (result interface{}, err error) {
+			aggregations := map[string]model.TermsAggrT{}
+			if len(response.Aggregations) > 0 {
+				err = json.Unmarshal(response.Aggregations, &aggregations)
+				if err != nil {
+					return result, err
+				}
+			}
+			return getTermAggregationResult(aggregations, field)
+		}
+		return request, handler, true, nil
+	case query.Aggregations != nil && query.Find == nil && query.ListIds == nil && query.CheckIds == nil && query.TermAggregate == nil:
+		request.Index = query.Resource
+		request.Body, err = this.getAggregationsBody(token, query.Resource, "r", query.AggregationScope, query.Aggregations)
+		if err != nil {
+			return request, handler, false, err
+		}
+		handler = func(response model.MsearchResponse[model.Entry]) (result interface{}, err error) {
+			return getBatchAggregationValues(query.Aggregations, response)
+		}
+		return request, handler, true, nil
+	default:
+		return request, handler, false, nil
+	}
+}
+
+func (this *Query) getBatchFindRequest(token auth.Token, kind string, find model.QueryFind, aggregations map[string]model.Aggregation) (request msearchRequest, handler batchHandler, err error) {
+	err = prepareFind(&find)
+	if err != nil {
+		return request, handler, err
+	}
+	if aggregations != nil {
+		find.WithTotal = true
+	}
+	body, err := this.getFindBody(token, kind, find)
+	if err != nil {
+		return request, handler, err
+	}
+	if aggregations != nil {
+		body["aggregations"] = getAggregationsRequest(aggregations)
+	}
+	body, err = this.getPaginatedBody(kind, body, find.QueryListCommons)
+	if err != nil {
+		return request, handler, err
+	}
+	handler = func(response model.MsearchResponse[model.Entry]) (result interface{}, err error) {
+		hits := response.Hits.Hits
+		elements := getHitResults(token, hits, find.Highlight)
+		if !find.WithTotal && !find.WithCursor {
+			return elements, nil
+		}
+		info := listInfo{Total: response.Hits.Total.Value}
+		if find.WithCursor && find.Limit > 0 && len(hits) == find.Limit {
+			info.NextCursor, err = this.encodeCursor(kind, body["sort"], hits[len(hits)-1].Sort, "")
+			if err != nil {
+				return result, err
+			}
+		}
+		if aggregations != nil {
+			facets, err := getBatchAggregationValues(aggregations, response)
+			if err != nil {
+				return result, err
+			}
+			return model.FindWithFacets{Total: info.Total, Result: elements, NextCursor: info.NextCursor, Facets: facets}, nil
+		}
+		return WithTotal{Total: info.Total, Result: elements, NextCursor: info.NextCursor}, nil
+	}
+	return msearchRequest{Index: kind, Body: body}, handler, nil
+}
+
+func getBatchAggregationValues(aggregations map[string]model.Aggregation, response model.MsearchResponse[model.Entry]) (result map[string]model.AggregationValue, err error) {
+	raw := map[string]json.RawMessage{}
+	if len(response.Aggregations) > 0 {
+		err = json.Unmarshal(response.Aggregations, &raw)
+		if err != nil {
+			return result, err
+		}
+	}
+	return getAggregationValues(aggregations, raw)
+}
+
+func getBatchResult(result interface{}, code int, err error) model.QueryBatchResult {
+	if err != nil {
+		if code < 300 {
+			code = model.GetErrCode(err)
+		}
+		return model.QueryBatchResult{Status: code, Error: err.Error()}
+	}
+	return model.QueryBatchResult{Status: http.StatusOK, Result: result}
+}
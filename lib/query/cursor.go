@@ -0,0 +1,110 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"log"
+	"strings"
+)
+
+// listCursor is the decoded form of the opaque next_cursor
+// Kind is the resource of the request that produced the cursor, to detect cursors (and their Pit) used with a different resource
+// Sort contains the sort of the request that produced the cursor, to detect cursors used with a different sort
+// Values are the raw sort values of the last hit; they are passed unchanged to search_after to keep the precision of long values
+// Pit references the point in time snapshot of consistent requests
+type listCursor struct {
+	Kind   string            `json:"k"`
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Pit    string            `json:"p,omitempty"`
+}
+
+// getCursorKey returns the key of the HMAC, which signs cursors, so that clients can not forge cursors (e.g. with the Pit of another resource)
+// without config.CursorSecret a random key is used, which invalidates cursors on restart and on other instances
+func getCursorKey(config configuration.Config) ([]byte, error) {
+	if config.CursorSecret != "" {
+		return []byte(config.CursorSecret), nil
+	}
+	log.Println("WARNING: no cursor_secret configured; cursors are only valid on this instance until restart")
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+func (this *Query) signCursor(payload string) string {
+	mac := hmac.New(sha256.New, this.cursorKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (this *Query) encodeCursor(kind string, sortBody interface{}, values []json.RawMessage, pit string) (string, error) {
+	sort, err := json.Marshal(sortBody)
+	if err != nil {
+		return "", err
+	}
+	temp, err := json.Marshal(listCursor{Kind: kind, Sort: string(sort), Values: values, Pit: pit})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(temp)
+	return payload + "." + this.signCursor(payload), nil
+}
+
+// decodeCursor parses cursor and checks that it was created for kind with sortBody
+func (this *Query) decodeCursor(cursor string, kind string, sortBody interface{}) (result listCursor, err error) {
+	decoded, err := this.parseCursor(cursor, kind)
+	if err != nil {
+		return result, err
+	}
+	sort, err := json.Marshal(sortBody)
+	if err != nil {
+		return result, err
+	}
+	if decoded.Sort != string(sort) {
+		return result, fmt.Errorf("%w: cursor was created with a different sort", model.ErrBadRequest)
+	}
+	return decoded, nil
+}
+
+// parseCursor verifies the signature of cursor, decodes it and checks that it was created for kind
+func (this *Query) parseCursor(cursor string, kind string) (result listCursor, err error) {
+	payload, signature, found := strings.Cut(cursor, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(this.signCursor(payload))) {
+		return result, fmt.Errorf("%w: invalid cursor signature", model.ErrBadRequest)
+	}
+	temp, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return result, fmt.Errorf("%w: invalid cursor: %v", model.ErrBadRequest, err.Error())
+	}
+	decoded := listCursor{}
+	err = json.Unmarshal(temp, &decoded)
+	if err != nil {
+		return result, fmt.Errorf("%w: invalid cursor: %v", model.ErrBadRequest, err.Error())
+	}
+	if decoded.Kind != kind {
+		return result, fmt.Errorf("%w: cursor was created for a different resource", model.ErrBadRequest)
+	}
+	return decoded, nil
+}
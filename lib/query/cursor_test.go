@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"strings"
+	"testing"
+)
+
+func TestCursorKeepsLongSortValues(t *testing.T) {
+	//sort values of a document without the sort field
+	hit := model.Hit[model.Entry]{}
+	err := json.Unmarshal([]byte(`{"_id": "d1", "sort": [9223372036854775807, "d1"]}`), &hit)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sortBody := []map[string]interface{}{{"annotations.connected": "asc"}, {"resource": "asc"}}
+	q := &Query{cursorKey: []byte("test")}
+	cursor, err := q.encodeCursor("devices", sortBody, hit.Sort, "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	after, err := q.getSearchAfter("devices", model.QueryListCommons{After: &model.ListAfter{Cursor: cursor}}, sortBody)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	temp, err := json.Marshal(map[string]interface{}{"search_after": after})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(temp) != `{"search_after":[9223372036854775807,"d1"]}` {
+		t.Error(string(temp))
+	}
+}
+
+func TestCursorOfOtherResource(t *testing.T) {
+	sortBody := []map[string]interface{}{{"resource": "asc"}}
+	q := &Query{cursorKey: []byte("test")}
+	cursor, err := q.encodeCursor("devices", sortBody, []json.RawMessage{json.RawMessage(`"d1"`)}, "pit")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_, err = q.decodeCursor(cursor, "devices", sortBody)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_, err = q.decodeCursor(cursor, "processmodel", sortBody)
+	if !errors.Is(err, model.ErrBadRequest) {
+		t.Error(err)
+	}
+}
+
+func TestForgedCursor(t *testing.T) {
+	sortBody := []map[string]interface{}{{"resource": "asc"}}
+	q := &Query{cursorKey: []byte("test")}
+	cursor, err := q.encodeCursor("devices", sortBody, []json.RawMessage{json.RawMessage(`"d1"`)}, "pit")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	payload, _, _ := strings.Cut(cursor, ".")
+	forged, err := json.Marshal(listCursor{Kind: "devices", Sort: `[{"resource":"asc"}]`, Values: []json.RawMessage{json.RawMessage(`"d1"`)}, Pit: "other-pit"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, c := range []string{
+		payload,
+		base64.RawURLEncoding.EncodeToString(forged) + "." + strings.SplitN(cursor, ".", 2)[1],
+	} {
+		_, err = q.decodeCursor(c, "devices", sortBody)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(c, err)
+		}
+	}
+	_, err = (&Query{cursorKey: []byte("other")}).decodeCursor(cursor, "devices", sortBody)
+	if !errors.Is(err, model.ErrBadRequest) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"net/http"
+)
+
+// ExplainQuery returns the index and body of the OpenSearch request Query would send for request.QueryMessage
+// without executing it; only admins may explain queries, because the _explain api of ExplainId ignores the rights of the token
+// Find and ListIds with 'consistent' would additionally reference a point in time instead of the index
+// Find with 'did_you_mean' may send a second request for spelling suggestions, which is not part of the result
+func (this *Query) ExplainQuery(tokenStr string, request model.QueryExplainRequest) (result model.QueryExplainResult, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return result, err
+	}
+	if !token.IsAdmin() {
+		return result, fmt.Errorf("%w: only admins may explain queries", model.ErrAccessDenied)
+	}
+	result.Index = request.Resource
+	result.Body, err = this.getQueryBody(token, request.QueryMessage)
+	if err != nil {
+		return result, err
+	}
+	if request.ExplainId != "" {
+		result.Explanation, err = this.explain(request.Resource, request.ExplainId, result.Body["query"])
+	}
+	return result, err
+}
+
+// getQueryBody returns the body Query sends for query, built with the same functions
+func (this *Query) getQueryBody(token auth.Token, query model.QueryMessage) (body map[string]interface{}, err error) {
+	err = this.validateAggregations(query)
+	if err != nil {
+		return body, err
+	}
+	count := 0
+	for _, set := range []bool{query.Find != nil, query.ListIds != nil, query.CheckIds != nil, query.TermAggregate != nil, query.Aggregations != nil && query.Find == nil} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return body, fmt.Errorf("%w: expect exactly one of 'find', 'list_ids', 'check_ids', 'term_aggregate' or 'aggregations'", model.ErrBadRequest)
+	}
+	switch {
+	case query.Find != nil:
+		find := *query.Find
+		body, err = this.getFindQueryBody(token, query.Resource, &find, query.Aggregations)
+		if err != nil {
+			return body, err
+		}
+		return this.getPaginatedBody(query.Resource, body, find.QueryListCommons)
+	case query.ListIds != nil:
+		listIds := *query.ListIds
+		body, _, err = this.getListIdsBody(token, query.Resource, &listIds)
+		if err != nil {
+			return body, err
+		}
+		return this.getPaginatedBody(query.Resource, body, listIds.QueryListCommons)
+	case query.CheckIds != nil:
+		body, _ = this.getCheckIdsBody(token, query.CheckIds.Ids, query.CheckIds.Rights)
+		return body, nil
+	case query.TermAggregate != nil:
+		return this.getTermAggregationBody(token, query.Resource, "r", query.AggregationScope, *query.TermAggregate, query.TermAggregateLimit)
+	default:
+		return this.getAggregationsBody(token, query.Resource, "r", query.AggregationScope, query.Aggregations)
+	}
+}
+
+// explain uses the OpenSearch _explain api to describe why the resource id does or does not match query
+func (this *Query) explain(kind string, id string, query interface{}) (result *model.QueryExplanation, err error) {
+	resp, err := this.opensearchClient.Explain(
+		kind,
+		id,
+		this.opensearchClient.Explain.WithContext(this.getTimeout()),
+		this.opensearchClient.Explain.WithBody(opensearchutil.NewJSONReader(map[string]interface{}{"query": query})),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return result, fmt.Errorf("%w: resource %v not found", model.ErrNotFound, id)
+	}
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := struct {
+		Matched     bool            `json:"matched"`
+		Explanation json.RawMessage `json:"explanation"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	return &model.QueryExplanation{Id: id, Matched: pl.Matched, Details: pl.Explanation}, nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/query/modifier"
+	"net/http"
+	"sort"
+)
+
+// FederatedSearch executes request.Find on every resource of request.Resources with one msearch request
+func (this *Query) FederatedSearch(tokenStr string, request model.FederatedSearchRequest) (result model.FederatedSearchResult, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return result, err
+	}
+	if request.Find.Limit == 0 {
+		request.Find.Limit = 100
+	}
+	if request.Find.Rights == "" {
+		request.Find.Rights = "r"
+	}
+	//the total is part of the result of every resource
+	request.Find.WithTotal = true
+	if request.Find.Search != "" && len(request.Find.GetSort()) == 0 {
+		request.Find.Sort = []model.SortField{{Field: model.SortByScore, Desc: true}}
+	}
+	err = request.Validate()
+	if err != nil {
+		return result, err
+	}
+	requests := []msearchRequest{}
+	for _, kind := range request.Resources {
+		if _, ok := this.config.Resources[kind]; !ok {
+			return result, fmt.Errorf("%w: unknown resource %v", model.ErrBadRequest, kind)
+		}
+		body, err := this.getFindBody(token, kind, request.Find)
+		if err != nil {
+			return result, err
+		}
+		body, err = this.getPaginatedBody(kind, body, request.Find.QueryListCommons)
+		if err != nil {
+			return result, err
+		}
+		if request.MergeByScore {
+			//opensearch omits the _score if the sort does not use it
+			body["track_scores"] = true
+		}
+		requests = append(requests, msearchRequest{Index: kind, Body: body})
+	}
+	responses, err := this.msearchEntries(requests)
+	if err != nil {
+		return result, err
+	}
+	result.Resources = map[string]model.FederatedSearchResourceResult{}
+	for i, response := range responses {
+		kind := request.Resources[i]
+		if response.Error != nil || response.Status > 299 {
+			err = fmt.Errorf("search of %v failed: %v", kind, response.Error)
+			if response.Status == http.StatusBadRequest {
+				err = fmt.Errorf("%w: %v", model.ErrBadRequest, err.Error())
+			}
+			return result, err
+		}
+		elements := getHitResults(token, response.Hits.Hits, request.Find.Highlight)
+		if len(request.Find.AddIdModifier) > 0 {
+			elements, err, _ = this.addParsedModifier(token, kind, elements, request.Find.AddIdModifier, request.Find.QueryListCommons)
+			if err != nil {
+				return result, err
+			}
+		}
+		if elements == nil {
+			elements = []map[string]interface{}{}
+		}
+		result.Resources[kind] = model.FederatedSearchResourceResult{
+			Total:  response.Hits.Total.Value,
+			Result: elements,
+		}
+		if request.MergeByScore {
+			result.Merged = append(result.Merged, getFederatedSearchElements(kind, response.Hits.Hits, elements)...)
+		}
+	}
+	if request.MergeByScore {
+		result.Merged = mergeFederatedSearchElements(result.Merged, request.Find.Limit)
+	}
+	return result, nil
+}
+
+// getFindBody returns the request body for find, without pagination and sort
+func (this *Query) getFindBody(token auth.Token, kind string, find model.QueryFind) (body map[string]interface{}, err error) {
+	if find.Search != "" {
+		return this.getSearchBody(
+			token,
+			kind,
+			find.Search,
+			searchOptions{Highlight: find.Highlight, Mode: find.SearchMode, Fuzziness: find.Fuzziness, Fields: find.SearchFields},
+			find.QueryListCommons,
+			find.Filter,
+			find.Params)
+	}
+	filter := getRightsQuery(find.Rights, token.GetUserId(), token.GetRoles())
+	if find.Filter != nil {
+		selectionFilter, err := this.GetFilter(token, kind, *find.Filter, find.Params)
+		if err != nil {
+			return body, err
+		}
+		filter = append(filter, selectionFilter)
+	}
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filter,
+			},
+		},
+	}, nil
+}
+
+// getFederatedSearchElements pairs the result elements of kind with the scores of their hits
+// elements may be reordered and their ids may be changed by id modifiers -> scores are matched by the id without modifier
+func getFederatedSearchElements(kind string, hits []model.Hit[model.Entry], elements []map[string]interface{}) (result []model.FederatedSearchElement) {
+	scores := map[string]float64{}
+	for _, hit := range hits {
+		scores[hit.Source.Resource], _ = hit.Score.(float64)
+	}
+	for _, element := range elements {
+		id, _ := element["id"].(string)
+		pureId, _ := modifier.SplitModifier(id)
+		result = append(result, model.FederatedSearchElement{
+			Resource: kind,
+			Score:    scores[pureId],
+			Element:  element,
+		})
+	}
+	return result
+}
+
+// mergeFederatedSearchElements orders elements by score; elements with equal scores keep the order of the request resources
+func mergeFederatedSearchElements(elements []model.FederatedSearchElement, limit int) []model.FederatedSearchElement {
+	sort.SliceStable(elements, func(i, j int) bool {
+		return elements[i].Score > elements[j].Score
+	})
+	if limit > 0 && len(elements) > limit {
+		elements = elements[:limit]
+	}
+	if elements == nil {
+		elements = []model.FederatedSearchElement{}
+	}
+	return elements
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"testing"
+)
+
+func TestFederatedSearchElementsWithModifiedIds(t *testing.T) {
+	hits := []model.Hit[model.Entry]{
+		{Score: 2.0, Source: model.Entry{Resource: "d1"}},
+		{Score: 1.0, Source: model.Entry{Resource: "d2"}},
+	}
+	elements := []map[string]interface{}{
+		{"id": "d2$service_group_selection=sg1"},
+		{"id": "d1$service_group_selection=sg1"},
+		{"id": "d1"},
+	}
+	result := getFederatedSearchElements("devices", hits, elements)
+	expected := []float64{1, 2, 2}
+	for i, element := range result {
+		if element.Score != expected[i] {
+			t.Error(i, element)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"strings"
+)
+
+// getHighlightBody highlights the fields that are copied to feature_search, or the searchFields if set
+// feature_search itself is not part of the _source and can not be highlighted,
+// so the FeatureSearchSubField of every source field, which is analyzed like feature_search, is highlighted with a match of the search
+// wildcards of the search are not repeated in the highlight_query; they would need a scan of all terms of the field
+func (this *Query) getHighlightBody(kind string, search string, searchFields []string) (map[string]interface{}, error) {
+	sources := this.getFeatureSearchSources(kind)
+	if len(searchFields) > 0 {
+		parsed, err := this.getSearchFields(kind, searchFields)
+		if err != nil {
+			return nil, err
+		}
+		sources = []featureSearchSource{}
+		for _, field := range parsed {
+			sources = append(sources, field.featureSearchSource)
+		}
+	}
+	fields := map[string]interface{}{}
+	for _, source := range sources {
+		field := source.Path + "." + model.FeatureSearchSubField
+		err := this.checkSubField(kind, field)
+		if err != nil {
+			return nil, err
+		}
+		highlight := map[string]interface{}{
+			"highlight_query": map[string]interface{}{
+				"match": map[string]interface{}{
+					field: map[string]interface{}{"query": strings.ReplaceAll(search, "*", " ")},
+				},
+			},
+		}
+		if source.Type != "text" {
+			//keyword values are short -> return the whole value instead of fragments
+			highlight["number_of_fragments"] = 0
+		}
+		fields[field] = highlight
+	}
+	return map[string]interface{}{
+		"pre_tags":  []string{"<em>"},
+		"post_tags": []string{"</em>"},
+		"fields":    fields,
+	}, nil
+}
+
+// getHighlightResult maps the highlighted field paths to the field names used in the result (e.g. 'features.name.search' -> 'name')
+func getHighlightResult(highlight map[string][]string) map[string][]string {
+	result := map[string][]string{}
+	for field, fragments := range highlight {
+		field = strings.TrimSuffix(field, "."+model.FeatureSearchSubField)
+		result[strings.TrimPrefix(field, "features.")] = fragments
+	}
+	return result
+}
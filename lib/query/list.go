@@ -0,0 +1,176 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"log"
+	"net/http"
+)
+
+// listInfo contains information about a list request, additional to the list elements
+type listInfo struct {
+	Total        int64
+	NextCursor   string
+	Aggregations map[string]json.RawMessage
+	Suggestions  []string
+}
+
+// searchEntries executes a list request with the query in body and the pagination and sort of queryCommons
+func (this *Query) searchEntries(token auth.Token, kind string, body map[string]interface{}, queryCommons model.QueryListCommons) (hits []model.Hit[model.Entry], info listInfo, err error) {
+	ctx := this.getTimeout()
+
+	options := []func(*opensearchapi.SearchRequest){
+		this.opensearchClient.Search.WithContext(ctx),
+	}
+	pitId := ""
+	if queryCommons.Consistent {
+		pitId, err = this.getListPit(token, kind, queryCommons)
+		if err != nil {
+			return hits, info, err
+		}
+		//searches with a point in time may not name an index
+		body["pit"] = map[string]interface{}{
+			"id":         pitId,
+			"keep_alive": opensearchclient.PitKeepAlive,
+		}
+	} else {
+		options = append(options, this.opensearchClient.Search.WithIndex(kind))
+	}
+	body, err = this.getPaginatedBody(kind, body, queryCommons)
+	if err != nil {
+		return hits, info, err
+	}
+	options = append(options, this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(body)))
+
+	resp, err := this.opensearchClient.Search(options...)
+	if err != nil {
+		return hits, info, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		if queryCommons.Consistent && resp.StatusCode == http.StatusNotFound {
+			return hits, info, fmt.Errorf("%w: the snapshot of the cursor is expired", model.ErrBadRequest)
+		}
+		return hits, info, errors.New(resp.String())
+	}
+	pl := model.AggregationsResult[model.Entry, map[string]json.RawMessage]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return hits, info, err
+	}
+	hits = pl.Hits.Hits
+	info.Total = pl.Hits.Total.Value
+	info.Aggregations = pl.Aggregations
+	if pl.PitId != "" {
+		pitId = pl.PitId
+	}
+	if queryCommons.WithCursor && queryCommons.Limit > 0 && len(hits) == queryCommons.Limit {
+		info.NextCursor, err = this.encodeCursor(kind, body["sort"], hits[len(hits)-1].Sort, pitId)
+		if err != nil {
+			return hits, info, err
+		}
+	} else if pitId != "" {
+		//last page -> the snapshot is no longer needed
+		this.openPits.remove(pitId)
+		err = opensearchclient.ClosePit(ctx, this.opensearchClient, pitId)
+		if err != nil {
+			log.Println("WARNING: unable to close point in time:", err)
+		}
+	}
+	return hits, info, nil
+}
+
+// getListPit returns the point in time of the cursor in queryCommons.After or opens a new one for the first page
+// the count of open points in time per user is limited by config.MaxOpenPitsPerUser
+func (this *Query) getListPit(token auth.Token, kind string, queryCommons model.QueryListCommons) (pitId string, err error) {
+	if queryCommons.After == nil || queryCommons.After.Cursor == "" {
+		err = this.openPits.check(token.GetUserId())
+		if err != nil {
+			return "", err
+		}
+		pitId, err = opensearchclient.OpenPit(this.getTimeout(), this.opensearchClient, kind)
+		if err != nil {
+			return "", err
+		}
+		this.openPits.use(token.GetUserId(), pitId)
+		return pitId, nil
+	}
+	cursor, err := this.decodeCursor(queryCommons.After.Cursor, kind, getSortBody(queryCommons))
+	if err != nil {
+		return "", err
+	}
+	if cursor.Pit == "" {
+		return "", fmt.Errorf("%w: cursor was not created by a consistent request", model.ErrBadRequest)
+	}
+	this.openPits.use(token.GetUserId(), cursor.Pit)
+	return cursor.Pit, nil
+}
+
+// CloseCursor releases the point in time snapshot of a cursor of a consistent list request before the last page is read
+// only the user that uses the cursor and admins may close it
+func (this *Query) CloseCursor(tokenStr string, kind string, cursorStr string) error {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return err
+	}
+	cursor, err := this.parseCursor(cursorStr, kind)
+	if err != nil {
+		return err
+	}
+	if cursor.Pit == "" {
+		return fmt.Errorf("%w: cursor was not created by a consistent request", model.ErrBadRequest)
+	}
+	if owner := this.openPits.getOwner(cursor.Pit); owner != "" && owner != token.GetUserId() && !token.IsAdmin() {
+		return fmt.Errorf("%w: cursor is used by another user", model.ErrAccessDenied)
+	}
+	this.openPits.remove(cursor.Pit)
+	return opensearchclient.ClosePit(this.getTimeout(), this.opensearchClient, cursor.Pit)
+}
+
+// getEntryResultList executes searchEntries and transforms the hits to the result format of the api
+func (this *Query) getEntryResultList(token auth.Token, kind string, body map[string]interface{}, queryCommons model.QueryListCommons) (result []map[string]interface{}, info listInfo, err error) {
+	hits, info, err := this.searchEntries(token, kind, body, queryCommons)
+	if err != nil {
+		return result, info, err
+	}
+	_, highlight := body["highlight"]
+	result = getHitResults(token, hits, highlight)
+	if len(queryCommons.AddIdModifier) > 0 {
+		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons)
+	}
+	return result, info, err
+}
+
+// getHitResults transforms hits to the result format of the api
+func getHitResults(token auth.Token, hits []model.Hit[model.Entry], highlight bool) (result []map[string]interface{}) {
+	for _, hit := range hits {
+		element := getEntryResult(hit.Source, token.GetUserId(), token.GetRoles())
+		if highlight {
+			element["highlight"] = getHighlightResult(hit.Highlight)
+		}
+		result = append(result, element)
+	}
+	return result
+}
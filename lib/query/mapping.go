@@ -0,0 +1,123 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"sort"
+	"strings"
+)
+
+var permissionMappingFields = []string{
+	"_id",
+	"resource",
+	"creator",
+	"admin_groups",
+	"admin_users",
+	"execute_groups",
+	"execute_users",
+	"read_groups",
+	"read_users",
+	"write_groups",
+	"write_users",
+}
+
+var numericMappingTypes = []string{"long", "integer", "short", "byte", "double", "float", "half_float", "scaled_float", "unsigned_long"}
+
+// getMappingType returns the type of field as described in the index_type_mapping of kind
+// field is expected in the form used by selections (e.g. 'features.name', 'annotations.connected' or 'features.attributes.key')
+// fields without explicit type but with properties are reported as 'object'
+func (this *Query) getMappingType(kind string, field string) (fieldType string, found bool) {
+	if contains(permissionMappingFields, field) {
+		return "keyword", true
+	}
+	parts := strings.Split(field, ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	properties, ok := this.config.IndexTypeMapping[kind][parts[0]]
+	if !ok {
+		return "", false
+	}
+	var current map[string]interface{}
+	for i, part := range parts[1:] {
+		if i > 0 {
+			//sub-fields may be described as object properties or as multi-fields
+			properties, ok = current["properties"].(map[string]interface{})
+			if !ok {
+				properties, ok = current["fields"].(map[string]interface{})
+			}
+			if !ok {
+				return "", false
+			}
+		}
+		current, ok = properties[part].(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+	}
+	fieldType, ok = current["type"].(string)
+	if !ok {
+		if _, hasProperties := current["properties"]; hasProperties {
+			return "object", true
+		}
+		return "", false
+	}
+	return fieldType, true
+}
+
+func isNumericMappingType(fieldType string) bool {
+	return contains(numericMappingTypes, fieldType)
+}
+
+// featureSearchSource is a field of the index_type_mapping that is copied to the feature_search field
+type featureSearchSource struct {
+	Path string
+	Type string
+}
+
+// getFeatureSearchSources returns all fields of kind with 'copy_to': 'feature_search', sorted by path
+func (this *Query) getFeatureSearchSources(kind string) (result []featureSearchSource) {
+	for _, prefix := range []string{"features", "annotations"} {
+		properties, ok := this.config.IndexTypeMapping[kind][prefix]
+		if ok {
+			result = append(result, findFeatureSearchSources(prefix, properties)...)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+func findFeatureSearchSources(prefix string, properties map[string]interface{}) (result []featureSearchSource) {
+	for name, value := range properties {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := prefix + "." + name
+		if model.CopiesToFeatureSearch(field["copy_to"]) {
+			fieldType, _ := field["type"].(string)
+			result = append(result, featureSearchSource{Path: path, Type: fieldType})
+		}
+		if sub, ok := field["properties"].(map[string]interface{}); ok {
+			result = append(result, findFeatureSearchSources(path, sub)...)
+		}
+	}
+	return result
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
 	"github.com/opensearch-project/opensearch-go/opensearchutil"
 )
 
@@ -38,6 +39,7 @@ func (this *Query) Import(imports map[string][]model.ResourceRights) (err error)
 
 func (this *Query) ImportResource(kind string, resource model.ResourceRights) (err error) {
 	ctx := this.getTimeout()
+	defer this.InvalidateRightsCache(kind, resource.ResourceId)
 	entry := model.Entry{Resource: resource.ResourceId, Features: resource.Features, Creator: resource.Creator}
 	entry.SetResourceRights(resource.ResourceRightsBase)
 	resp, err := this.opensearchClient.Index(
@@ -67,21 +69,26 @@ func (this *Query) Export(token string) (exports map[string][]model.ResourceRigh
 	return
 }
 
-func (this *Query) ExportKindAll(token string, kind string) (result []model.ResourceRights, err error) {
+// ExportKindAll exports all resources of kind from a point in time snapshot, to be consistent with concurrent writes
+func (this *Query) ExportKindAll(tokenStr string, kind string) (result []model.ResourceRights, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if !token.IsAdmin() {
+		return nil, errors.New("only admins may export")
+	}
 	result = []model.ResourceRights{}
-	limit := 100
-	offset := 0
-	for {
-		temp, err := this.ExportKind(token, kind, limit, offset)
-		if err != nil {
-			return result, err
-		}
-		result = append(result, temp...)
-		if len(temp) < limit {
-			return result, err
-		}
-		offset = offset + limit
+	query := map[string]interface{}{
+		"match_all": map[string]interface{}{},
 	}
+	err = opensearchclient.IterateEntries(context.Background(), this.opensearchClient, kind, query, 100, func(entries []model.Entry) error {
+		for _, entry := range entries {
+			result = append(result, entry.ToResourceRights())
+		}
+		return nil
+	})
+	return result, err
 }
 
 func (this *Query) ExportKind(tokenStr string, kind string, limit int, offset int) (result []model.ResourceRights, err error) {
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+)
+
+// msearchRequest is one search of a msearch request
+// body contains query, pagination and sort, because msearch has no per search request options
+type msearchRequest struct {
+	Index string
+	Body  map[string]interface{}
+}
+
+// msearchEntries executes multiple searches with one request
+// the responses are in the order of requests; failed searches are returned as responses with Error set
+func (this *Query) msearchEntries(requests []msearchRequest) (result []model.MsearchResponse[model.Entry], err error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, request := range requests {
+		err = encoder.Encode(map[string]interface{}{"index": request.Index})
+		if err != nil {
+			return result, err
+		}
+		err = encoder.Encode(request.Body)
+		if err != nil {
+			return result, err
+		}
+	}
+	resp, err := this.opensearchClient.Msearch(buf, this.opensearchClient.Msearch.WithContext(this.getTimeout()))
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := model.MsearchResult[model.Entry]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	if len(pl.Responses) != len(requests) {
+		return result, errors.New("unexpected count of msearch responses")
+	}
+	return pl.Responses, nil
+}
+
+// getPaginatedBody adds the pagination, sort and source filter of queryCommons to body
+// used for single searches (see searchEntries), msearch requests and ExplainQuery, so that all send the same body
+func (this *Query) getPaginatedBody(kind string, body map[string]interface{}, queryCommons model.QueryListCommons) (_ map[string]interface{}, err error) {
+	body["size"] = queryCommons.Limit
+	sortBody := getSortBody(queryCommons)
+	if queryCommons.After == nil {
+		body["from"] = queryCommons.Offset
+	} else {
+		body["search_after"], err = this.getSearchAfter(kind, queryCommons, sortBody)
+		if err != nil {
+			return body, err
+		}
+	}
+	body["sort"] = sortBody
+	body["version"] = true
+	if queryCommons.WithTotal {
+		body["track_total_hits"] = true
+	}
+	if _, ok := body["_source"]; !ok {
+		if sourceFilter := this.getSourceFilter(kind, queryCommons); sourceFilter != nil {
+			body["_source"] = sourceFilter
+		}
+	}
+	return body, nil
+}
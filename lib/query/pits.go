@@ -0,0 +1,103 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
+	"sync"
+	"time"
+)
+
+const defaultMaxOpenPitsPerUser = 5
+
+// openPits tracks the point in time snapshots of consistent list requests per user,
+// so that a single user can not exhaust search.max_open_pit_context of OpenSearch
+// entries expire like the snapshots after opensearchclient.PitKeepAlive without use; the count is local to this instance
+type openPits struct {
+	mux       sync.Mutex
+	max       int
+	keepAlive time.Duration
+	pits      map[string]map[string]time.Time //user -> pit id -> expiration
+}
+
+func newOpenPits(config configuration.Config) (*openPits, error) {
+	keepAlive, err := time.ParseDuration(opensearchclient.PitKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	max := int(config.MaxOpenPitsPerUser)
+	if max <= 0 {
+		max = defaultMaxOpenPitsPerUser
+	}
+	return &openPits{
+		max:       max,
+		keepAlive: keepAlive,
+		pits:      map[string]map[string]time.Time{},
+	}, nil
+}
+
+// check returns a bad request error if user may not open another point in time
+func (this *openPits) check(user string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	now := time.Now()
+	for pit, expiration := range this.pits[user] {
+		if now.After(expiration) {
+			delete(this.pits[user], pit)
+		}
+	}
+	if len(this.pits[user]) >= this.max {
+		return fmt.Errorf("%w: at most %v consistent cursors may be open; read them to the last page or close them with DELETE /v3/cursors/:resource/:cursor", model.ErrBadRequest, this.max)
+	}
+	return nil
+}
+
+// use adds or renews the point in time pit of user
+func (this *openPits) use(user string, pit string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.pits[user] == nil {
+		this.pits[user] = map[string]time.Time{}
+	}
+	this.pits[user][pit] = time.Now().Add(this.keepAlive)
+}
+
+// getOwner returns the user that uses pit or "" if pit is unknown
+func (this *openPits) getOwner(pit string) string {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	for user, pits := range this.pits {
+		if _, ok := pits[pit]; ok {
+			return user
+		}
+	}
+	return ""
+}
+
+func (this *openPits) remove(pit string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	for user, pits := range this.pits {
+		delete(pits, pit)
+		if len(pits) == 0 {
+			delete(this.pits, user)
+		}
+	}
+}
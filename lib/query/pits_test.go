@@ -0,0 +1,58 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"testing"
+	"time"
+)
+
+func TestOpenPits(t *testing.T) {
+	pits, err := newOpenPits(&configuration.ConfigStruct{MaxOpenPitsPerUser: 2})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pits.use("u1", "p1")
+	pits.use("u1", "p2")
+	if err = pits.check("u1"); !errors.Is(err, model.ErrBadRequest) {
+		t.Error(err)
+	}
+	if err = pits.check("u2"); err != nil {
+		t.Error(err)
+	}
+	if owner := pits.getOwner("p1"); owner != "u1" {
+		t.Error(owner)
+	}
+	pits.remove("p1")
+	if err = pits.check("u1"); err != nil {
+		t.Error(err)
+	}
+	if owner := pits.getOwner("p1"); owner != "" {
+		t.Error(owner)
+	}
+
+	//expired snapshots are not counted
+	pits.use("u1", "p3")
+	pits.pits["u1"]["p3"] = time.Now().Add(-time.Second)
+	if err = pits.check("u1"); err != nil {
+		t.Error(err)
+	}
+}
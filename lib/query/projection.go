@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"path"
+	"strings"
+)
+
+// getSourceFilter returns the _source filter for the include_fields and exclude_fields of queryCommons
+// and the default_exclude_fields of the resource config, or nil if all fields are requested
+// default excludes are ignored for fields that are explicitly listed in include_fields
+func (this *Query) getSourceFilter(kind string, queryCommons model.QueryListCommons) map[string]interface{} {
+	includes := toSourceFields(queryCommons.IncludeFields)
+	excludes := toSourceFields(queryCommons.ExcludeFields)
+	for _, field := range toSourceFields(this.config.Resources[kind].DefaultExcludeFields) {
+		if !contains(includes, field) {
+			excludes = append(excludes, field)
+		}
+	}
+	if len(includes) == 0 && len(excludes) == 0 {
+		return nil
+	}
+	result := map[string]interface{}{}
+	if len(includes) > 0 {
+		//the permission fields are needed for id, creator, permissions, shared and permission_holders
+		for _, field := range permissionMappingFields {
+			if field != "_id" {
+				includes = append(includes, field)
+			}
+		}
+		result["includes"] = includes
+	}
+	if len(excludes) > 0 {
+		result["excludes"] = excludes
+	}
+	return result
+}
+
+// toSourceFields adds the 'features.' prefix to fields that are neither features nor annotations
+func toSourceFields(fields []string) (result []string) {
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.HasPrefix(field, "features.") && !strings.HasPrefix(field, "annotations.") {
+			field = "features." + field
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// projectEntry applies a _source filter to an entry that could not be filtered by OpenSearch (e.g. entries modified by id modifiers)
+// only top level features and annotations are removed
+func projectEntry(entry model.Entry, filter map[string]interface{}) model.Entry {
+	if filter == nil {
+		return entry
+	}
+	includes, _ := filter["includes"].([]string)
+	excludes, _ := filter["excludes"].([]string)
+	keep := func(field string) bool {
+		//an include of a sub-field keeps the parent field
+		if len(includes) > 0 && !matchesSourceField(includes, field, true) {
+			return false
+		}
+		return !matchesSourceField(excludes, field, false)
+	}
+	features := map[string]interface{}{}
+	for key, value := range entry.Features {
+		if keep("features." + key) {
+			features[key] = value
+		}
+	}
+	entry.Features = features
+	annotations := map[string]interface{}{}
+	for key, value := range entry.Annotations {
+		if keep("annotations." + key) {
+			annotations[key] = value
+		}
+	}
+	entry.Annotations = annotations
+	return entry
+}
+
+func matchesSourceField(patterns []string, field string, matchParent bool) bool {
+	for _, pattern := range patterns {
+		if pattern == field || (matchParent && strings.HasPrefix(pattern, field+".")) {
+			return true
+		}
+		if matched, _ := path.Match(pattern, field); matched {
+			return true
+		}
+	}
+	return false
+}
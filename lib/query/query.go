@@ -46,6 +46,11 @@ type Query struct {
 	opensearchClient *opensearch.Client
 	timeout          time.Duration
 	modifier         *modifier.Modifier
+	savedQueries     map[string]map[string]model.QueryFind
+	rightsCache      *rightsCache //nil if disabled
+	openPits         *openPits
+	missingSubFields map[string]map[string]bool //kind -> multi-field path; see loadMissingSubFields
+	cursorKey        []byte
 }
 
 func New(config configuration.Config) (result *Query, err error) {
@@ -54,6 +59,26 @@ func New(config configuration.Config) (result *Query, err error) {
 		log.Println("ERROR: unable to parse config.Timeout", err)
 		return result, err
 	}
+	savedQueries, err := getSavedQueries(config)
+	if err != nil {
+		log.Println("ERROR: unable to load config.SavedQueries", err)
+		return result, err
+	}
+	rightsCache, err := newRightsCache(config)
+	if err != nil {
+		log.Println("ERROR: unable to parse config.RightsCacheTtl", err)
+		return result, err
+	}
+	openPits, err := newOpenPits(config)
+	if err != nil {
+		log.Println("ERROR: unable to parse opensearchclient.PitKeepAlive", err)
+		return result, err
+	}
+	cursorKey, err := getCursorKey(config)
+	if err != nil {
+		log.Println("ERROR: unable to create cursor key", err)
+		return result, err
+	}
 	client, err := opensearchclient.New(config)
 	if err != nil {
 		return result, err
@@ -62,8 +87,17 @@ func New(config configuration.Config) (result *Query, err error) {
 		config:           config,
 		opensearchClient: client,
 		timeout:          timeout,
+		savedQueries:     savedQueries,
+		rightsCache:      rightsCache,
+		openPits:         openPits,
+		cursorKey:        cursorKey,
 	}
 	result.modifier = modifier.New(config, result)
+	err = result.loadMissingSubFields(result.getTimeout())
+	if err != nil {
+		log.Println("ERROR: unable to compare index mappings", err)
+		return result, err
+	}
 	return result, err
 }
 
@@ -233,7 +267,7 @@ func (this *Query) CheckUserOrGroup(tokenStr string, kind string, resource strin
 
 func (this *Query) CheckUserOrGroupFromAuthToken(token auth.Token, kind string, resource string, rights string) (err error) {
 	pureId, _ := modifier.SplitModifier(resource)
-	e, _, err := this.GetResourceEntry(kind, pureId)
+	e, err := this.getResourceRights(kind, pureId)
 	if errors.Is(err, model.ErrNotFound) {
 		return model.ErrAccessDenied
 	}
@@ -282,26 +316,10 @@ func containsAny(list []string, any []string) bool {
 func (this *Query) CheckListUserOrGroup(token auth.Token, kind string, ids []string, rights string) (allowed map[string]bool, err error) {
 	allowed = map[string]bool{}
 	ctx := this.getTimeout()
-	terms := []interface{}{}
-	pureIds, preparedModify := this.modifier.PrepareListModify(ids)
-	for _, id := range pureIds {
-		terms = append(terms, id)
-	}
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"filter": append(getRightsQuery(rights, token.GetUserId(), token.GetRoles()), map[string]interface{}{
-					"terms": map[string]interface{}{
-						"resource": terms,
-					},
-				}),
-			},
-		},
-	}
+	query, preparedModify := this.getCheckIdsBody(token, ids, rights)
 	resp, err := this.opensearchClient.Search(
 		this.opensearchClient.Search.WithContext(ctx),
 		this.opensearchClient.Search.WithIndex(kind),
-		this.opensearchClient.Search.WithSize(len(pureIds)),
 		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(query)),
 	)
 	if err != nil {
@@ -325,20 +343,64 @@ func (this *Query) CheckListUserOrGroup(token auth.Token, kind string, ids []str
 	return allowed, nil
 }
 
-func (this *Query) getListFromIds(token auth.Token, kind string, ids []string, queryCommons model.QueryListCommons) (result []map[string]interface{}, total int64, err error) {
-	ctx := this.getTimeout()
-	terms := []interface{}{}
-
+// getCheckIdsBody returns the search body of CheckListUserOrGroup; used by Query and ExplainQuery
+func (this *Query) getCheckIdsBody(token auth.Token, ids []string, rights string) (body map[string]interface{}, preparedModify map[string][]modifier.PreparedModifyInfo) {
 	pureIds, preparedModify := this.modifier.PrepareListModify(ids)
+	body = getIdsBody(token, rights, pureIds)
+	body["size"] = len(pureIds)
+	return body, preparedModify
+}
 
-	for _, id := range pureIds {
-		terms = append(terms, id)
+// getListIdsBody sets the defaults of listIds and returns its search body without pagination; used by Query and ExplainQuery
+func (this *Query) getListIdsBody(token auth.Token, kind string, listIds *model.QueryListIds) (body map[string]interface{}, preparedModify map[string][]modifier.PreparedModifyInfo, err error) {
+	if listIds.Limit == 0 {
+		listIds.Limit = 100
 	}
+	if listIds.Rights == "" {
+		listIds.Rights = "r"
+	}
+	err = listIds.QueryListCommons.Validate()
+	if err != nil {
+		return body, preparedModify, err
+	}
+	pureIds, preparedModify := this.modifier.PrepareListModify(listIds.Ids)
+	body = getIdsBody(token, listIds.Rights, pureIds)
+	//modifiers may need fields that are not part of the requested projection -> project after the modification
+	if this.getSourceFilter(kind, listIds.QueryListCommons) != nil {
+		body["_source"] = true
+	}
+	return body, preparedModify, nil
+}
 
-	query := map[string]interface{}{
+// getFindQueryBody prepares find and returns its search body without pagination; used by Query and ExplainQuery
+func (this *Query) getFindQueryBody(token auth.Token, kind string, find *model.QueryFind, aggregations map[string]model.Aggregation) (body map[string]interface{}, err error) {
+	err = prepareFind(find)
+	if err != nil {
+		return body, err
+	}
+	if aggregations != nil {
+		find.WithTotal = true
+	}
+	body, err = this.getFindBody(token, kind, *find)
+	if err != nil {
+		return body, err
+	}
+	if aggregations != nil {
+		body["aggregations"] = getAggregationsRequest(aggregations)
+	}
+	return body, nil
+}
+
+// getIdsBody returns a search body for the resources with the given ids and rights
+func getIdsBody(token auth.Token, rights string, ids []string) map[string]interface{} {
+	terms := []interface{}{}
+	for _, id := range ids {
+		terms = append(terms, id)
+	}
+	return map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
-				"filter": append(getRightsQuery(queryCommons.Rights, token.GetUserId(), token.GetRoles()), map[string]interface{}{
+				"filter": append(getRightsQuery(rights, token.GetUserId(), token.GetRoles()), map[string]interface{}{
 					"terms": map[string]interface{}{
 						"resource": terms,
 					},
@@ -346,45 +408,41 @@ func (this *Query) getListFromIds(token auth.Token, kind string, ids []string, q
 			},
 		},
 	}
+}
 
-	options := []func(*opensearchapi.SearchRequest){
-		this.opensearchClient.Search.WithContext(ctx),
-		this.opensearchClient.Search.WithIndex(kind),
-	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, query, queryCommons)...)
-	if queryCommons.WithTotal {
-		options = append(options, this.opensearchClient.Search.WithTrackTotalHits(true))
-	}
+func (this *Query) getListFromIds(token auth.Token, kind string, ids []string, queryCommons model.QueryListCommons) (result []map[string]interface{}, info listInfo, err error) {
+	pureIds, preparedModify := this.modifier.PrepareListModify(ids)
+	return this.getModifiedListFromIds(token, kind, getIdsBody(token, queryCommons.Rights, pureIds), preparedModify, queryCommons)
+}
 
-	resp, err := this.opensearchClient.Search(options...)
-	if err != nil {
-		return result, 0, err
-	}
-	defer resp.Body.Close()
-	if resp.IsError() {
-		return result, 0, errors.New(resp.String())
+// getModifiedListFromIds executes query, which has to be restricted to the pure ids of preparedModify, and applies the result modifiers
+func (this *Query) getModifiedListFromIds(token auth.Token, kind string, query map[string]interface{}, preparedModify map[string][]modifier.PreparedModifyInfo, queryCommons model.QueryListCommons) (result []map[string]interface{}, info listInfo, err error) {
+
+	//modifiers may need fields that are not part of the requested projection -> project after the modification
+	sourceFilter := this.getSourceFilter(kind, queryCommons)
+	if sourceFilter != nil {
+		query["_source"] = true
 	}
-	pl := model.SearchResult[model.Entry]{}
-	err = json.NewDecoder(resp.Body).Decode(&pl)
+
+	hits, info, err := this.searchEntries(token, kind, query, queryCommons)
 	if err != nil {
-		return result, 0, err
+		return result, info, err
 	}
 	modifyCache := modifier.NewModifyResourceReferenceCache()
-	total = pl.Hits.Total.Value
-	for _, hit := range pl.Hits.Hits {
+	for _, hit := range hits {
 		entry := hit.Source
 		modifiedResults, err := this.modifier.UsePreparedModify(preparedModify, entry, kind, modifyCache)
 		if err != nil {
-			return result, 0, err
+			return result, info, err
 		}
 		for _, modifiedResult := range modifiedResults {
-			result = append(result, getEntryResult(modifiedResult, token.GetUserId(), token.GetRoles()))
+			result = append(result, getEntryResult(projectEntry(modifiedResult, sourceFilter), token.GetUserId(), token.GetRoles()))
 		}
 	}
 	if len(queryCommons.AddIdModifier) > 0 {
-		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons.Rights, queryCommons.SortBy, queryCommons.SortDesc)
+		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons)
 	}
-	return result, total, nil
+	return result, info, err
 }
 
 func (this *Query) GetListFromIds(token auth.Token, kind string, ids []string, queryCommons model.QueryListCommons) (result []map[string]interface{}, err error) {
@@ -457,9 +515,7 @@ func (this *Query) getListForUserOrGroup(kind string, user string, groups []stri
 	return
 }
 
-func (this *Query) getList(token auth.Token, kind string, queryCommons model.QueryListCommons) (result []map[string]interface{}, total int64, err error) {
-	ctx := this.getTimeout()
-
+func (this *Query) getList(token auth.Token, kind string, queryCommons model.QueryListCommons) (result []map[string]interface{}, info listInfo, err error) {
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
@@ -467,38 +523,7 @@ func (this *Query) getList(token auth.Token, kind string, queryCommons model.Que
 			},
 		},
 	}
-
-	options := []func(*opensearchapi.SearchRequest){
-		this.opensearchClient.Search.WithContext(ctx),
-		this.opensearchClient.Search.WithIndex(kind),
-		this.opensearchClient.Search.WithVersion(true),
-	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, query, queryCommons)...)
-	if queryCommons.WithTotal {
-		options = append(options, this.opensearchClient.Search.WithTrackTotalHits(true))
-	}
-
-	resp, err := this.opensearchClient.Search(options...)
-	if err != nil {
-		return result, 0, err
-	}
-	defer resp.Body.Close()
-	if resp.IsError() {
-		return result, 0, errors.New(resp.String())
-	}
-	pl := model.SearchResult[model.Entry]{}
-	err = json.NewDecoder(resp.Body).Decode(&pl)
-	if err != nil {
-		return result, 0, err
-	}
-	total = pl.Hits.Total.Value
-	for _, hit := range pl.Hits.Hits {
-		result = append(result, getEntryResult(hit.Source, token.GetUserId(), token.GetRoles()))
-	}
-	if len(queryCommons.AddIdModifier) > 0 {
-		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons.Rights, queryCommons.SortBy, queryCommons.SortDesc)
-	}
-	return
+	return this.getEntryResultList(token, kind, query, queryCommons)
 }
 
 func (this *Query) GetList(token auth.Token, kind string, queryCommons model.QueryListCommons) (result []map[string]interface{}, err error) {
@@ -696,12 +721,15 @@ func (this *Query) SearchRightsToAdministrate(kind string, user string, groups [
 	}
 	ctx := this.getTimeout()
 
-	searchOperation, searchConfig := this.getFeatureSearchInfo(query)
+	searchQuery, err := this.getSearchQuery(kind, query, searchOptions{})
+	if err != nil {
+		return result, err
+	}
 	body := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"filter": getRightsQuery("a", user, groups),
-				"must":   []map[string]interface{}{{searchOperation: searchConfig}},
+				"must":   []map[string]interface{}{searchQuery},
 			},
 		},
 	}
@@ -747,8 +775,7 @@ func (this *Query) SearchListAll(kind string, query string, user string, groups
 	return
 }
 
-func (this *Query) selectByFeature(token auth.Token, kind string, feature string, value string, queryCommons model.QueryListCommons) (result []map[string]interface{}, total int64, err error) {
-	ctx := this.getTimeout()
+func (this *Query) selectByFeature(token auth.Token, kind string, feature string, value string, queryCommons model.QueryListCommons) (result []map[string]interface{}, info listInfo, err error) {
 	if !strings.HasPrefix(feature, "features.") && !strings.HasPrefix(feature, "annotations.") {
 		feature = "features." + feature
 	}
@@ -764,37 +791,7 @@ func (this *Query) selectByFeature(token auth.Token, kind string, feature string
 			},
 		},
 	}
-	options := []func(*opensearchapi.SearchRequest){
-		this.opensearchClient.Search.WithContext(ctx),
-		this.opensearchClient.Search.WithIndex(kind),
-	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, query, queryCommons)...)
-	if queryCommons.WithTotal {
-		options = append(options, this.opensearchClient.Search.WithTrackTotalHits(true))
-	}
-
-	resp, err := this.opensearchClient.Search(options...)
-	if err != nil {
-		return result, 0, err
-	}
-	defer resp.Body.Close()
-	if resp.IsError() {
-		return result, 0, errors.New(resp.String())
-	}
-	pl := model.SearchResult[model.Entry]{}
-	err = json.NewDecoder(resp.Body).Decode(&pl)
-	if err != nil {
-		return result, 0, err
-	}
-
-	total = pl.Hits.Total.Value
-	for _, hit := range pl.Hits.Hits {
-		result = append(result, getEntryResult(hit.Source, token.GetUserId(), token.GetRoles()))
-	}
-	if len(queryCommons.AddIdModifier) > 0 {
-		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons.Rights, queryCommons.SortBy, queryCommons.SortDesc)
-	}
-	return
+	return this.getEntryResultList(token, kind, query, queryCommons)
 }
 
 func (this *Query) SelectByFeature(token auth.Token, kind string, feature string, value string, queryCommons model.QueryListCommons) (result []map[string]interface{}, err error) {
@@ -857,103 +854,99 @@ func (this *Query) selectByField(kind string, field string, value string, user s
 }
 
 func (this *Query) getFeatureSearchInfo(query string) (operator string, config map[string]interface{}) {
+	return this.getFeatureSearchInfoForField("feature_search", query)
+}
+
+// getFeatureSearchInfoForField creates the default text search on field, which is expected to be analyzed like feature_search
+func (this *Query) getFeatureSearchInfoForField(field string, query string) (operator string, config map[string]interface{}) {
 	search := strings.TrimSpace(query)
 	if strings.Contains(search, "*") {
 		return "wildcard", map[string]interface{}{
-			"feature_search": map[string]interface{}{"case_insensitive": true, "value": search},
+			field: map[string]interface{}{"case_insensitive": true, "value": search},
 		}
 	}
 	if !this.config.EnableCombinedWildcardFeatureSearch || strings.ContainsAny(search, " -/_:,;([{&%$") {
 		return "match", map[string]interface{}{
-			"feature_search": map[string]interface{}{"operator": "AND", "query": search},
+			field: map[string]interface{}{"operator": "AND", "query": search},
 		}
 	}
 	return "bool", map[string]interface{}{
 		"should": []map[string]interface{}{
 			{
 				"wildcard": map[string]interface{}{
-					"feature_search": map[string]interface{}{"case_insensitive": true, "value": "*" + search + "*"},
+					field: map[string]interface{}{"case_insensitive": true, "value": "*" + search + "*"},
 				},
 			},
 			{
 				"match": map[string]interface{}{
-					"feature_search": map[string]interface{}{"operator": "AND", "query": search},
+					field: map[string]interface{}{"operator": "AND", "query": search},
 				},
 			},
 		},
 	}
 }
 
-func (this *Query) searchList(token auth.Token, kind string, query string, queryCommons model.QueryListCommons, selection *model.Selection) (result []map[string]interface{}, total int64, err error) {
+func (this *Query) searchList(token auth.Token, kind string, query string, options searchOptions, queryCommons model.QueryListCommons, selection *model.Selection, params map[string]interface{}) (result []map[string]interface{}, info listInfo, err error) {
+	if len(queryCommons.GetSort()) == 0 {
+		queryCommons.Sort = []model.SortField{{Field: model.SortByScore, Desc: true}}
+	}
+	body, err := this.getSearchBody(token, kind, query, options, queryCommons, selection, params)
+	if err != nil {
+		return result, info, err
+	}
+	return this.getEntryResultList(token, kind, body, queryCommons)
+}
+
+// getSearchBody returns the request body of a permission filtered text search, without pagination and sort
+func (this *Query) getSearchBody(token auth.Token, kind string, query string, options searchOptions, queryCommons model.QueryListCommons, selection *model.Selection, params map[string]interface{}) (body map[string]interface{}, err error) {
 	filter := getRightsQuery(queryCommons.Rights, token.GetUserId(), token.GetRoles())
 	if selection != nil {
-		selectionFilter, err := this.GetFilter(token, *selection)
+		selectionFilter, err := this.GetFilter(token, kind, *selection, params)
 		if err != nil {
-			return result, 0, err
+			return body, err
 		}
 		filter = append(filter, selectionFilter)
 	}
-	searchOperation, searchConfig := this.getFeatureSearchInfo(query)
-	body := map[string]interface{}{
+	searchQuery, err := this.getSearchQuery(kind, query, options)
+	if err != nil {
+		return body, err
+	}
+	body = map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"filter": filter,
-				"must":   []map[string]interface{}{{searchOperation: searchConfig}},
+				"must":   []map[string]interface{}{searchQuery},
 			},
 		},
 	}
-
-	ctx := this.getTimeout()
-
-	options := []func(*opensearchapi.SearchRequest){
-		this.opensearchClient.Search.WithContext(ctx),
-		this.opensearchClient.Search.WithIndex(kind),
-		this.opensearchClient.Search.WithVersion(true),
-	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, body, queryCommons)...)
-	if queryCommons.WithTotal {
-		options = append(options, this.opensearchClient.Search.WithTrackTotalHits(true))
-	}
-
-	resp, err := this.opensearchClient.Search(options...)
-	if err != nil {
-		return result, 0, err
-	}
-	defer resp.Body.Close()
-	if resp.IsError() {
-		return result, 0, errors.New(resp.String())
-	}
-	pl := model.SearchResult[model.Entry]{}
-	err = json.NewDecoder(resp.Body).Decode(&pl)
-	if err != nil {
-		return result, 0, err
-	}
-	total = pl.Hits.Total.Value
-	for _, hit := range pl.Hits.Hits {
-		result = append(result, getEntryResult(hit.Source, token.GetUserId(), token.GetRoles()))
-	}
-	if len(queryCommons.AddIdModifier) > 0 {
-		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons.Rights, queryCommons.SortBy, queryCommons.SortDesc)
+	if options.Highlight {
+		body["highlight"], err = this.getHighlightBody(kind, query, options.Fields)
+		if err != nil {
+			return body, err
+		}
 	}
-	return
+	return body, nil
 }
 
 // SearchList does a text search with query on the feature_search index
 // the function allows optionally additional filtering with the selection parameter. when unneeded this parameter may be nil.
 func (this *Query) SearchList(token auth.Token, kind string, query string, queryCommons model.QueryListCommons, selection *model.Selection) (result []map[string]interface{}, err error) {
-	result, _, err = this.searchList(token, kind, query, queryCommons, selection)
+	result, _, err = this.searchList(token, kind, query, searchOptions{}, queryCommons, selection, nil)
 	return
 }
 
 func (this *Query) searchListAll(kind string, query string, user string, groups []string, rights string, limit int, offset int) (result []map[string]interface{}, err error) {
 	ctx := this.getTimeout()
 
-	searchOperation, searchConfig := this.getFeatureSearchInfo(query)
+	searchQuery, err := this.getSearchQuery(kind, query, searchOptions{})
+	if err != nil {
+		return result, err
+	}
 	body := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"filter": getRightsQuery(rights, user, groups),
-				"must":   []map[string]interface{}{{searchOperation: searchConfig}},
+				"must":   []map[string]interface{}{searchQuery},
 			},
 		},
 	}
@@ -987,12 +980,15 @@ func (this *Query) searchListAll(kind string, query string, user string, groups
 func (this *Query) SearchOrderedList(kind string, query string, user string, groups []string, queryCommons model.QueryListCommons) (result []map[string]interface{}, err error) {
 	ctx := this.getTimeout()
 
-	searchOperation, searchConfig := this.getFeatureSearchInfo(query)
+	searchQuery, err := this.getSearchQuery(kind, query, searchOptions{})
+	if err != nil {
+		return result, err
+	}
 	body := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"filter": getRightsQuery(queryCommons.Rights, user, groups),
-				"must":   []map[string]interface{}{{searchOperation: searchConfig}},
+				"must":   []map[string]interface{}{searchQuery},
 			},
 		},
 	}
@@ -1002,7 +998,11 @@ func (this *Query) SearchOrderedList(kind string, query string, user string, gro
 		this.opensearchClient.Search.WithIndex(kind),
 		this.opensearchClient.Search.WithVersion(true),
 	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, body, queryCommons)...)
+	pagination, err := this.withPaginationAndBody(this.opensearchClient.Search, kind, body, queryCommons)
+	if err != nil {
+		return result, err
+	}
+	options = append(options, pagination...)
 
 	resp, err := this.opensearchClient.Search(options...)
 	if err != nil {
@@ -1088,11 +1088,11 @@ func getPermissions(entry model.Entry, user string, groups []string) (result map
 	return
 }
 
-func (this *Query) getListWithSelection(token auth.Token, kind string, queryCommons model.QueryListCommons, selection model.Selection) (result []map[string]interface{}, total int64, err error) {
+func (this *Query) getListWithSelection(token auth.Token, kind string, queryCommons model.QueryListCommons, selection model.Selection, params map[string]interface{}) (result []map[string]interface{}, info listInfo, err error) {
 	filter := getRightsQuery(queryCommons.Rights, token.GetUserId(), token.GetRoles())
-	selectionFilter, err := this.GetFilter(token, selection)
+	selectionFilter, err := this.GetFilter(token, kind, selection, params)
 	if err != nil {
-		return result, 0, err
+		return result, info, err
 	}
 	filter = append(filter, selectionFilter)
 	body := map[string]interface{}{
@@ -1102,49 +1102,36 @@ func (this *Query) getListWithSelection(token auth.Token, kind string, queryComm
 			},
 		},
 	}
+	return this.getEntryResultList(token, kind, body, queryCommons)
+}
 
-	ctx := this.getTimeout()
+func (this *Query) GetListWithSelection(token auth.Token, kind string, queryCommons model.QueryListCommons, selection model.Selection) (result []map[string]interface{}, err error) {
+	result, _, err = this.getListWithSelection(token, kind, queryCommons, selection, nil)
+	return
+}
 
-	options := []func(*opensearchapi.SearchRequest){
-		this.opensearchClient.Search.WithContext(ctx),
-		this.opensearchClient.Search.WithIndex(kind),
-		this.opensearchClient.Search.WithVersion(true),
-	}
-	options = append(options, withPaginationAndBody(this.opensearchClient.Search, body, queryCommons)...)
-	if queryCommons.WithTotal {
-		options = append(options, this.opensearchClient.Search.WithTrackTotalHits(true))
-	}
+type WithTotal = model.WithTotal
 
-	resp, err := this.opensearchClient.Search(options...)
-	if err != nil {
-		return result, 0, err
+// prepareFind sets the defaults of find and validates it
+func prepareFind(find *model.QueryFind) error {
+	if find.Limit == 0 {
+		find.Limit = 100
 	}
-	if resp.IsError() {
-		return result, 0, errors.New(resp.String())
+	if find.Rights == "" {
+		find.Rights = "r"
 	}
-	pl := model.SearchResult[model.Entry]{}
-	err = json.NewDecoder(resp.Body).Decode(&pl)
+	err := find.QueryListCommons.Validate()
 	if err != nil {
-		return result, 0, err
+		return err
 	}
-	total = pl.Hits.Total.Value
-	for _, hit := range pl.Hits.Hits {
-		result = append(result, getEntryResult(hit.Source, token.GetUserId(), token.GetRoles()))
+	err = find.ValidateSearchOptions()
+	if err != nil {
+		return err
 	}
-	if len(queryCommons.AddIdModifier) > 0 {
-		result, err, _ = this.addParsedModifier(token, kind, result, queryCommons.AddIdModifier, queryCommons.Rights, queryCommons.SortBy, queryCommons.SortDesc)
+	if find.Search != "" && len(find.GetSort()) == 0 {
+		find.Sort = []model.SortField{{Field: model.SortByScore, Desc: true}}
 	}
-	return
-}
-
-func (this *Query) GetListWithSelection(token auth.Token, kind string, queryCommons model.QueryListCommons, selection model.Selection) (result []map[string]interface{}, err error) {
-	result, _, err = this.getListWithSelection(token, kind, queryCommons, selection)
-	return
-}
-
-type WithTotal struct {
-	Total  int64       `json:"total"`
-	Result interface{} `json:"result"`
+	return nil
 }
 
 func (this *Query) Query(tokenStr string, query model.QueryMessage) (result interface{}, code int, err error) {
@@ -1152,49 +1139,43 @@ func (this *Query) Query(tokenStr string, query model.QueryMessage) (result inte
 	if err != nil {
 		return result, model.GetErrCode(err), err
 	}
+	err = this.validateAggregations(query)
+	if err != nil {
+		return result, model.GetErrCode(err), err
+	}
 	if query.Find != nil {
-		var total int64
-		if query.Find.Limit == 0 {
-			query.Find.Limit = 100
-		}
-		if query.Find.Rights == "" {
-			query.Find.Rights = "r"
-		}
-		err = query.Find.QueryListCommons.Validate()
+		var info listInfo
+		var body map[string]interface{}
+		body, err = this.getFindQueryBody(token, query.Resource, query.Find, query.Aggregations)
 		if err != nil {
 			return result, model.GetErrCode(err), err
 		}
-		if query.Find.Search == "" {
-			if query.Find.Filter == nil {
-				result, total, err = this.getList(
-					token,
-					query.Resource,
-					query.Find.QueryListCommons)
-			} else {
-				result, total, err = this.getListWithSelection(
-					token,
-					query.Resource,
-					query.Find.QueryListCommons,
-					*query.Find.Filter)
-			}
-		} else {
-			result, total, err = this.searchList(
-				token,
-				query.Resource,
-				query.Find.Search,
-				query.Find.QueryListCommons,
-				query.Find.Filter)
+		result, info, err = this.getEntryResultList(token, query.Resource, body, query.Find.QueryListCommons)
+		if err == nil && query.Find.DidYouMean && info.Total == 0 {
+			info.Suggestions, err = this.getSpellingSuggestions(token, query.Resource, query.Find.Search, query.Find.SearchFields, query.Find.Rights)
 		}
 		if len(query.Find.AddIdModifier) > 0 {
-			result, err, code = this.addParsedModifier(token, query.Resource, result.([]map[string]interface{}), query.Find.AddIdModifier, query.Find.Rights, query.Find.SortBy, query.Find.SortDesc)
+			result, err, code = this.addParsedModifier(token, query.Resource, result.([]map[string]interface{}), query.Find.AddIdModifier, query.Find.QueryListCommons)
 			if err != nil {
 				return
 			}
 		}
-		if query.Find.QueryListCommons.WithTotal {
+		if err == nil && query.Aggregations != nil {
+			var facets map[string]model.AggregationValue
+			facets, err = getAggregationValues(query.Aggregations, info.Aggregations)
+			result = model.FindWithFacets{
+				Total:       info.Total,
+				Result:      result,
+				NextCursor:  info.NextCursor,
+				Facets:      facets,
+				Suggestions: info.Suggestions,
+			}
+		} else if query.Find.QueryListCommons.WithTotal || query.Find.QueryListCommons.WithCursor || query.Find.DidYouMean {
 			result = WithTotal{
-				Total:  total,
-				Result: result,
+				Total:       info.Total,
+				Result:      result,
+				NextCursor:  info.NextCursor,
+				Suggestions: info.Suggestions,
 			}
 		}
 	}
@@ -1208,39 +1189,34 @@ func (this *Query) Query(tokenStr string, query model.QueryMessage) (result inte
 	}
 
 	if query.ListIds != nil {
-		var total int64
-		if query.ListIds.Limit == 0 {
-			query.ListIds.Limit = 100
-		}
-		if query.ListIds.Rights == "" {
-			query.ListIds.Rights = "r"
-		}
-		err = query.ListIds.QueryListCommons.Validate()
+		var info listInfo
+		body, preparedModify, err := this.getListIdsBody(token, query.Resource, query.ListIds)
 		if err != nil {
 			return result, model.GetErrCode(err), err
 		}
-		result, total, err = this.getListFromIds(
-			token,
-			query.Resource,
-			query.ListIds.Ids,
-			query.ListIds.QueryListCommons)
+		result, info, err = this.getModifiedListFromIds(token, query.Resource, body, preparedModify, query.ListIds.QueryListCommons)
 
 		if len(query.ListIds.AddIdModifier) > 0 {
-			result, err, code = this.addParsedModifier(token, query.Resource, result.([]map[string]interface{}), query.ListIds.AddIdModifier, query.ListIds.Rights, query.ListIds.SortBy, query.ListIds.SortDesc)
+			result, err, code = this.addParsedModifier(token, query.Resource, result.([]map[string]interface{}), query.ListIds.AddIdModifier, query.ListIds.QueryListCommons)
 			if err != nil {
 				return result, code, err
 			}
 		}
-		if query.ListIds.QueryListCommons.WithTotal {
+		if query.ListIds.QueryListCommons.WithTotal || query.ListIds.QueryListCommons.WithCursor {
 			result = WithTotal{
-				Total:  total,
-				Result: result,
+				Total:      info.Total,
+				Result:     result,
+				NextCursor: info.NextCursor,
 			}
 		}
 	}
 
 	if query.TermAggregate != nil {
-		result, err = this.getTermAggregation(token, query.Resource, "r", *query.TermAggregate, query.TermAggregateLimit)
+		result, err = this.getTermAggregation(token, query.Resource, "r", query.AggregationScope, *query.TermAggregate, query.TermAggregateLimit)
+	}
+
+	if query.Aggregations != nil && query.Find == nil {
+		result, err = this.getAggregations(token, query.Resource, query.AggregationScope, query.Aggregations)
 	}
 	if err != nil && code == 0 {
 		code = model.GetErrCode(err)
@@ -1249,32 +1225,100 @@ func (this *Query) Query(tokenStr string, query model.QueryMessage) (result inte
 }
 
 func (this *Query) List(tokenStr string, kind string, options model.ListOptions) (result []map[string]interface{}, err error) {
-	token, err := auth.Parse(tokenStr)
+	result, _, err = this.list(tokenStr, kind, options)
+	return
+}
+
+// ListWithTotal works like List but wraps the result with the total count and the next_cursor
+func (this *Query) ListWithTotal(tokenStr string, kind string, options model.ListOptions) (result WithTotal, err error) {
+	list, info, err := this.list(tokenStr, kind, options)
 	if err != nil {
 		return result, err
 	}
-	mode, err := options.Mode()
+	return WithTotal{
+		Total:       info.Total,
+		Result:      list,
+		NextCursor:  info.NextCursor,
+		Suggestions: info.Suggestions,
+	}, nil
+}
+
+func (this *Query) list(tokenStr string, kind string, options model.ListOptions) (result []map[string]interface{}, info listInfo, err error) {
+	token, err := auth.Parse(tokenStr)
 	if err != nil {
-		return result, err
+		return result, info, err
 	}
-	err = options.QueryListCommons.Validate()
+	err = options.Validate()
 	if err != nil {
-		return result, err
+		return result, info, err
 	}
-	switch mode {
-	case model.ListOptionsModeTextSearch:
-		return this.SearchList(token, kind, options.TextSearch, options.QueryListCommons, nil)
-	case model.ListOptionsModeSelection:
-		//options.Mode() guaranties that options.Selection is not empty; panic otherwise
-		return this.SelectByFeature(token, kind, options.Selection.Feature, options.Selection.Value, options.QueryListCommons)
-	case model.ListOptionsModeListIds:
-		return this.GetListFromIds(token, kind, options.ListIds, options.QueryListCommons)
-	default:
-		return this.GetList(token, kind, options.QueryListCommons)
+	if options.TextSearch != "" && len(options.GetSort()) == 0 {
+		options.Sort = []model.SortField{{Field: model.SortByScore, Desc: true}}
+	}
+	if len(options.ListIds) > 0 {
+		pureIds, preparedModify := this.modifier.PrepareListModify(options.ListIds)
+		body, err := this.getListOptionsBody(token, kind, options, pureIds)
+		if err != nil {
+			return result, info, err
+		}
+		result, info, err = this.getModifiedListFromIds(token, kind, body, preparedModify, options.QueryListCommons)
+	} else {
+		body, err := this.getListOptionsBody(token, kind, options, nil)
+		if err != nil {
+			return result, info, err
+		}
+		result, info, err = this.getEntryResultList(token, kind, body, options.QueryListCommons)
+	}
+	if err == nil && options.DidYouMean && info.Total == 0 {
+		info.Suggestions, err = this.getSpellingSuggestions(token, kind, options.TextSearch, options.SearchFields, options.Rights)
+	}
+	return result, info, err
+}
+
+// getListOptionsBody returns the search body of options without pagination
+// pureIds are the ids of options.ListIds without result modifiers (see modifier.PrepareListModify)
+// List and Total use the same body, so that Total counts exactly the resources List pages through
+func (this *Query) getListOptionsBody(token auth.Token, kind string, options model.ListOptions, pureIds []string) (body map[string]interface{}, err error) {
+	selection, err := options.GetSelection()
+	if err != nil {
+		return body, err
+	}
+	if len(options.ListIds) > 0 {
+		idsSelection := model.Selection{Condition: model.ConditionConfig{
+			Feature:   "resource",
+			Operation: model.QueryAnyValueInFeatureOperation,
+			Value:     pureIds,
+		}}
+		if selection == nil {
+			selection = &idsSelection
+		} else {
+			selection = &model.Selection{And: []model.Selection{idsSelection, *selection}}
+		}
+	}
+	if options.TextSearch != "" {
+		return this.getSearchBody(token, kind, options.TextSearch, searchOptions{Highlight: options.Highlight, Mode: options.SearchMode, Fuzziness: options.Fuzziness, Fields: options.SearchFields}, options.QueryListCommons, selection, nil)
+	}
+	filter := getRightsQuery(options.Rights, token.GetUserId(), token.GetRoles())
+	if selection != nil {
+		selectionFilter, err := this.GetFilter(token, kind, *selection, nil)
+		if err != nil {
+			return body, err
+		}
+		filter = append(filter, selectionFilter)
 	}
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filter,
+			},
+		},
+	}, nil
 }
 
-func (this *Query) addParsedModifier(token auth.Token, resourceKind string, elements []map[string]interface{}, parsedModifier map[string][]string, rights string, sortBy string, sortDesc bool) (result []map[string]interface{}, err error, code int) {
+// addParsedModifier replaces elements with the result of their ids combined with parsedModifier
+// the rights, sort and field projection are taken from queryCommons
+func (this *Query) addParsedModifier(token auth.Token, resourceKind string, elements []map[string]interface{}, parsedModifier map[string][]string, queryCommons model.QueryListCommons) (result []map[string]interface{}, err error, code int) {
+	sortFields := queryCommons.GetSort()
 	if len(elements) == 0 {
 		return elements, nil, http.StatusOK
 	}
@@ -1299,15 +1343,28 @@ func (this *Query) addParsedModifier(token auth.Token, resourceKind string, elem
 		idList = append(idList, modifier.JoinModifier(pureId, parameter))
 	}
 	result, err = this.GetListFromIds(token, resourceKind, idList, model.QueryListCommons{
-		Limit:    len(idList),
-		Offset:   0,
-		Rights:   rights,
-		SortBy:   sortBy,
-		SortDesc: sortDesc,
+		Limit:         len(idList),
+		Offset:        0,
+		Rights:        queryCommons.Rights,
+		Sort:          sortFields,
+		IncludeFields: queryCommons.IncludeFields,
+		ExcludeFields: queryCommons.ExcludeFields,
 	})
 	if err != nil {
 		return nil, err, http.StatusInternalServerError
 	}
+	if len(sortFields) > 0 && sortFields[0].Field == model.SortByScore {
+		//the id list has no relevance score -> keep the order of the elements
+		index := map[string]int{}
+		for i, id := range idList {
+			index[id] = i
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			a, _ := result[i]["id"].(string)
+			b, _ := result[j]["id"].(string)
+			return index[a] < index[b]
+		})
+	}
 	return result, nil, http.StatusOK
 }
 
@@ -1317,7 +1374,7 @@ func (this *Query) AddModifier(token auth.Token, resourceKind string, elements [
 		err = fmt.Errorf("invalid add_id_modifier value: %w", err)
 		return nil, err, http.StatusBadRequest
 	}
-	return this.addParsedModifier(token, resourceKind, elements, parsedModifier, rights, sortBy, sortDesc)
+	return this.addParsedModifier(token, resourceKind, elements, parsedModifier, model.QueryListCommons{Rights: rights, SortBy: sortBy, SortDesc: sortDesc})
 }
 
 func getSharedState(reqUser string, entry model.Entry) bool {
@@ -1362,49 +1419,87 @@ func hasAdminGroup(entry model.Entry, groups []string) bool {
 	return false
 }
 
-func withPaginationAndBody(search opensearchapi.Search, query map[string]interface{}, queryCommons model.QueryListCommons) (result []func(*opensearchapi.SearchRequest)) {
-	defaultSort := "resource"
-	s := defaultSort
-	if queryCommons.SortBy != "" {
-		s = queryCommons.SortBy
-	}
-	if s == "id" {
-		s = defaultSort
-	}
-	if s != defaultSort && !strings.HasPrefix(s, "features.") && !strings.HasPrefix(s, "annotations.") {
-		s = "features." + s
-	}
+func (this *Query) withPaginationAndBody(search opensearchapi.Search, kind string, query map[string]interface{}, queryCommons model.QueryListCommons) (result []func(*opensearchapi.SearchRequest), err error) {
 	result = append(result, search.WithSize(queryCommons.Limit))
+	sortBody := getSortBody(queryCommons)
 	if queryCommons.After == nil {
 		result = append(result, search.WithFrom(queryCommons.Offset))
 	} else {
-		query["search_after"] = []interface{}{queryCommons.After.Id}
+		query["search_after"], err = this.getSearchAfter(kind, queryCommons, sortBody)
+		if err != nil {
+			return result, err
+		}
+	}
+	query["sort"] = sortBody
+	result = append(result, search.WithBody(opensearchutil.NewJSONReader(query)))
+	return result, nil
+}
+
+// getSearchAfter returns the search_after values of queryCommons.After
+func (this *Query) getSearchAfter(kind string, queryCommons model.QueryListCommons, sortBody interface{}) (interface{}, error) {
+	if queryCommons.After.Cursor != "" {
+		cursor, err := this.decodeCursor(queryCommons.After.Cursor, kind, sortBody)
+		if err != nil {
+			return nil, err
+		}
+		return cursor.Values, nil
 	}
-	if queryCommons.SortDesc {
-		query["sort"] = []map[string]interface{}{
-			{s: "desc"},
+	return []interface{}{queryCommons.After.Id}, nil
+}
+
+// getSortBody translates the sort of queryCommons to an OpenSearch sort
+// resource is appended as tie-breaker with the direction of the last sort field
+func getSortBody(queryCommons model.QueryListCommons) (result []map[string]interface{}) {
+	defaultSort := "resource"
+	sortFields := queryCommons.GetSort()
+	if len(sortFields) == 0 {
+		sortFields = []model.SortField{{Field: defaultSort, Desc: queryCommons.SortDesc}}
+	}
+	result = []map[string]interface{}{}
+	containsDefaultSort := false
+	for _, field := range sortFields {
+		s := field.Field
+		if s == model.SortByScore {
+			//best matches first; a lower score is never useful
+			result = append(result, map[string]interface{}{s: "desc"})
+			continue
 		}
-		if s != defaultSort {
-			query["sort"] = []map[string]interface{}{
-				{s: "desc"},
-				{"resource": "desc"},
-			}
+		s = getIndexField(s)
+		if s == defaultSort {
+			containsDefaultSort = true
 		}
-	} else {
-		query["sort"] = []map[string]interface{}{
-			{s: "asc"},
+		order := "asc"
+		if field.Desc {
+			order = "desc"
 		}
-		if s != defaultSort {
-			query["sort"] = []map[string]interface{}{
-				{s: "asc"},
-				{"resource": "asc"},
-			}
+		if field.Missing != "" {
+			result = append(result, map[string]interface{}{s: map[string]interface{}{"order": order, "missing": "_" + field.Missing}})
+		} else {
+			result = append(result, map[string]interface{}{s: order})
 		}
 	}
-	result = append(result, search.WithBody(opensearchutil.NewJSONReader(query)))
+	if !containsDefaultSort {
+		order := "asc"
+		if last := sortFields[len(sortFields)-1]; last.Desc && last.Field != model.SortByScore {
+			order = "desc"
+		}
+		result = append(result, map[string]interface{}{defaultSort: order})
+	}
 	return result
 }
 
+// getIndexField translates a field of the api to the field in the index
+// 'id' is translated to 'resource'; fields without 'features.' or 'annotations.' prefix are features
+func getIndexField(field string) string {
+	if field == "id" {
+		return "resource"
+	}
+	if field != "resource" && !strings.HasPrefix(field, "features.") && !strings.HasPrefix(field, "annotations.") {
+		return "features." + field
+	}
+	return field
+}
+
 func contains(list []string, value string) bool {
 	for _, element := range list {
 		if element == value {
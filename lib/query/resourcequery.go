@@ -0,0 +1,131 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+)
+
+const maxInResourceQueryDepth = 2
+
+// getInResourceQueryFilter resolves the ids of the inner resource query, filtered by the permissions of token,
+// and returns a terms filter on feature
+// the inner query may return at most config.InResourceQueryLimit ids; bigger results are answered with a bad request error
+// inner queries may contain 'in_resource_query' conditions themselves, up to maxInResourceQueryDepth levels
+func (this *Query) getInResourceQueryFilter(token auth.Token, feature string, value interface{}, params map[string]interface{}, depth int) (map[string]interface{}, error) {
+	if depth >= maxInResourceQueryDepth {
+		return nil, fmt.Errorf("%w: '%v' conditions may be nested at most %v levels deep", model.ErrBadRequest, model.QueryInResourceQueryOperation, maxInResourceQueryDepth)
+	}
+	resourceQuery, err := toResourceQuery(value)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := this.getIdsOfResourceQuery(token, resourceQuery, params, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"terms": map[string]interface{}{
+			feature: ids,
+		},
+	}, nil
+}
+
+func toResourceQuery(value interface{}) (result model.ResourceQuery, err error) {
+	switch v := value.(type) {
+	case model.ResourceQuery:
+		result = v
+	case *model.ResourceQuery:
+		if v != nil {
+			result = *v
+		}
+	default:
+		temp, err := json.Marshal(value)
+		if err != nil {
+			return result, fmt.Errorf("%w: %v", model.ErrBadRequest, err.Error())
+		}
+		err = json.Unmarshal(temp, &result)
+		if err != nil {
+			return result, fmt.Errorf("%w: operation '%v' expects value like {\"resource\": \"\", \"rights\": \"\", \"filter\": {}}: %v", model.ErrBadRequest, model.QueryInResourceQueryOperation, err.Error())
+		}
+	}
+	if result.Resource == "" {
+		return result, fmt.Errorf("%w: operation '%v' expects value.resource", model.ErrBadRequest, model.QueryInResourceQueryOperation)
+	}
+	if result.Rights == "" {
+		result.Rights = "r"
+	}
+	return result, nil
+}
+
+func (this *Query) getIdsOfResourceQuery(token auth.Token, resourceQuery model.ResourceQuery, params map[string]interface{}, depth int) (ids []string, err error) {
+	if _, ok := this.config.Resources[resourceQuery.Resource]; !ok {
+		return nil, fmt.Errorf("%w: unknown resource %v in '%v' condition", model.ErrBadRequest, resourceQuery.Resource, model.QueryInResourceQueryOperation)
+	}
+	limit := this.config.InResourceQueryLimit
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: operation '%v' is disabled by in_resource_query_limit", model.ErrBadRequest, model.QueryInResourceQueryOperation)
+	}
+	filter := getRightsQuery(resourceQuery.Rights, token.GetUserId(), token.GetRoles())
+	if resourceQuery.Filter != nil {
+		selectionFilter, err := this.getFilter(token, resourceQuery.Resource, *resourceQuery.Filter, params, depth)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, selectionFilter)
+	}
+	body := map[string]interface{}{
+		"_source": false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filter,
+			},
+		},
+	}
+	ctx := this.getTimeout()
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(ctx),
+		this.opensearchClient.Search.WithIndex(resourceQuery.Resource),
+		this.opensearchClient.Search.WithSize(int(limit)+1), //one more than allowed to detect overflow
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(body)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return nil, errors.New(resp.String())
+	}
+	pl := model.SearchResult[model.Entry]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(pl.Hits.Hits)) > limit {
+		return nil, fmt.Errorf("%w: '%v' condition on %v matches more than %v elements", model.ErrBadRequest, model.QueryInResourceQueryOperation, resourceQuery.Resource, limit)
+	}
+	ids = []string{}
+	for _, hit := range pl.Hits.Hits {
+		ids = append(ids, hit.Id)
+	}
+	return ids, nil
+}
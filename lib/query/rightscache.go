@@ -0,0 +1,214 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"sync"
+	"time"
+)
+
+const defaultRightsCacheTtl = 30 * time.Second
+
+// rightsCache is a bounded lru cache with ttl of the rights of resources, used by CheckUserOrGroupFromAuthToken
+// unknown resources are cached as well, so that repeated checks of deleted resources do not reach opensearch
+type rightsCache struct {
+	mux     sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List //most recently used first
+
+	//incremented by every invalidation; results of reads that started before an invalidation are not cached,
+	//because they may have been read before the change that caused the invalidation
+	generation uint64
+
+	hits          uint64
+	misses        uint64
+	evictions     uint64
+	invalidations uint64
+}
+
+type rightsCacheEntry struct {
+	key     string
+	rights  model.Entry //only Resource and the rights fields are set
+	found   bool
+	expires time.Time
+}
+
+// newRightsCache returns nil if the cache is disabled by config.RightsCacheSize
+func newRightsCache(config configuration.Config) (*rightsCache, error) {
+	if config.RightsCacheSize <= 0 {
+		return nil, nil
+	}
+	ttl := defaultRightsCacheTtl
+	if config.RightsCacheTtl != "" {
+		var err error
+		ttl, err = time.ParseDuration(config.RightsCacheTtl)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &rightsCache{
+		maxSize: int(config.RightsCacheSize),
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}, nil
+}
+
+func getRightsCacheKey(kind string, resource string) string {
+	return kind + "/" + resource
+}
+
+// load returns the cached rights of the resource or uses read and caches its result
+// returns model.ErrNotFound for unknown resources
+func (this *rightsCache) load(kind string, resource string, read func() (model.Entry, error)) (result model.Entry, err error) {
+	key := getRightsCacheKey(kind, resource)
+	this.mux.Lock()
+	if element, ok := this.entries[key]; ok {
+		entry := element.Value.(*rightsCacheEntry)
+		if time.Now().Before(entry.expires) {
+			this.hits++
+			this.order.MoveToFront(element)
+			this.mux.Unlock()
+			if !entry.found {
+				return result, model.ErrNotFound
+			}
+			return entry.rights, nil
+		}
+		this.remove(element)
+	}
+	this.misses++
+	generation := this.generation
+	this.mux.Unlock()
+
+	result, err = read()
+	found := err == nil
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return result, err
+	}
+	result = getRightsEntry(result)
+
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.generation == generation {
+		this.add(&rightsCacheEntry{key: key, rights: result, found: found, expires: time.Now().Add(this.ttl)})
+	}
+	return result, err
+}
+
+func (this *rightsCache) add(entry *rightsCacheEntry) {
+	if element, ok := this.entries[entry.key]; ok {
+		element.Value = entry
+		this.order.MoveToFront(element)
+		return
+	}
+	this.entries[entry.key] = this.order.PushFront(entry)
+	for this.order.Len() > this.maxSize {
+		this.remove(this.order.Back())
+		this.evictions++
+	}
+}
+
+func (this *rightsCache) remove(element *list.Element) {
+	this.order.Remove(element)
+	delete(this.entries, element.Value.(*rightsCacheEntry).key)
+}
+
+func (this *rightsCache) invalidate(kind string, resource string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.generation++
+	this.invalidations++
+	if element, ok := this.entries[getRightsCacheKey(kind, resource)]; ok {
+		this.remove(element)
+	}
+}
+
+func (this *rightsCache) stats() (result model.RightsCacheStats) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	result = model.RightsCacheStats{
+		Enabled:       true,
+		Size:          this.order.Len(),
+		MaxSize:       this.maxSize,
+		Ttl:           this.ttl.String(),
+		Hits:          this.hits,
+		Misses:        this.misses,
+		Evictions:     this.evictions,
+		Invalidations: this.invalidations,
+	}
+	if this.hits+this.misses > 0 {
+		result.HitRate = float64(this.hits) / float64(this.hits+this.misses)
+	}
+	return result
+}
+
+func getRightsEntry(entry model.Entry) model.Entry {
+	return model.Entry{
+		Resource:      entry.Resource,
+		AdminUsers:    entry.AdminUsers,
+		AdminGroups:   entry.AdminGroups,
+		ReadUsers:     entry.ReadUsers,
+		ReadGroups:    entry.ReadGroups,
+		WriteUsers:    entry.WriteUsers,
+		WriteGroups:   entry.WriteGroups,
+		ExecuteUsers:  entry.ExecuteUsers,
+		ExecuteGroups: entry.ExecuteGroups,
+	}
+}
+
+// getResourceRights returns the rights of a resource as model.Entry without features and annotations
+// uses the rights cache if enabled
+func (this *Query) getResourceRights(kind string, resource string) (result model.Entry, err error) {
+	read := func() (model.Entry, error) {
+		entry, _, err := this.GetResourceEntry(kind, resource)
+		return entry, err
+	}
+	if this.rightsCache == nil {
+		return read()
+	}
+	return this.rightsCache.load(kind, resource, read)
+}
+
+// InvalidateRightsCache removes the resource from the rights cache
+// called by the worker after every change of a resource and for every message of the done_topic (see worker.InitRightsCacheInvalidation)
+func (this *Query) InvalidateRightsCache(kind string, resource string) {
+	if this.rightsCache != nil {
+		this.rightsCache.invalidate(kind, resource)
+	}
+}
+
+func (this *Query) GetRightsCacheStats(tokenStr string) (result model.RightsCacheStats, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return result, err
+	}
+	if !token.IsAdmin() {
+		return result, fmt.Errorf("%w: only admins may read the rights cache stats", model.ErrAccessDenied)
+	}
+	if this.rightsCache == nil {
+		return result, nil
+	}
+	return this.rightsCache.stats(), nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRightsCache(t *testing.T) {
+	cache, err := newRightsCache(&configuration.ConfigStruct{RightsCacheSize: 2, RightsCacheTtl: "1h"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	reads := 0
+	read := func(entry model.Entry, err error) func() (model.Entry, error) {
+		return func() (model.Entry, error) {
+			reads++
+			return entry, err
+		}
+	}
+	d1 := model.Entry{Resource: "d1", Features: map[string]interface{}{"name": "foo"}, ReadUsers: []string{"u1"}}
+	d2 := model.Entry{Resource: "d2", ReadUsers: []string{"u2"}}
+
+	t.Run("miss", func(t *testing.T) {
+		result, err := cache.load("devices", "d1", read(d1, nil))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected := model.Entry{Resource: "d1", ReadUsers: []string{"u1"}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("%#v", result)
+		}
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		result, err := cache.load("devices", "d1", read(model.Entry{}, errors.New("unexpected read")))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Resource != "d1" {
+			t.Errorf("%#v", result)
+		}
+	})
+
+	t.Run("not found is cached", func(t *testing.T) {
+		_, err := cache.load("devices", "unknown", read(model.Entry{}, model.ErrNotFound))
+		if !errors.Is(err, model.ErrNotFound) {
+			t.Error(err)
+		}
+		_, err = cache.load("devices", "unknown", read(model.Entry{}, errors.New("unexpected read")))
+		if !errors.Is(err, model.ErrNotFound) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("other errors are not cached", func(t *testing.T) {
+		_, err := cache.load("devices", "d2", read(model.Entry{}, errors.New("timeout")))
+		if err == nil || err.Error() != "timeout" {
+			t.Error(err)
+		}
+		_, err = cache.load("devices", "d2", read(d2, nil))
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("size is bounded", func(t *testing.T) {
+		//d1 is the least recently used entry and has been evicted by d2
+		if cache.order.Len() != 2 {
+			t.Error(cache.order.Len())
+		}
+		before := reads
+		_, err := cache.load("devices", "d1", read(d1, nil))
+		if err != nil {
+			t.Error(err)
+		}
+		if reads != before+1 {
+			t.Error(reads, before)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		cache.invalidate("devices", "d1")
+		changed := model.Entry{Resource: "d1", ReadUsers: []string{"u1", "u3"}}
+		result, err := cache.load("devices", "d1", read(changed, nil))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(result.ReadUsers, changed.ReadUsers) {
+			t.Errorf("%#v", result)
+		}
+	})
+
+	t.Run("read before invalidation is not cached", func(t *testing.T) {
+		cache.invalidate("devices", "d2")
+		_, err := cache.load("devices", "d2", func() (model.Entry, error) {
+			cache.invalidate("devices", "d2")
+			return d2, nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if _, ok := cache.entries[getRightsCacheKey("devices", "d2")]; ok {
+			t.Error("expected d2 to be not cached")
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		cache.ttl = time.Millisecond
+		_, err := cache.load("devices", "d2", read(d2, nil))
+		if err != nil {
+			t.Error(err)
+		}
+		time.Sleep(2 * time.Millisecond)
+		before := reads
+		_, err = cache.load("devices", "d2", read(d2, nil))
+		if err != nil {
+			t.Error(err)
+		}
+		if reads != before+1 {
+			t.Error(reads, before)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		stats := cache.stats()
+		if stats.Hits != 2 || stats.Misses != 9 || stats.Invalidations != 3 || stats.Evictions == 0 || stats.Size != 2 || stats.MaxSize != 2 {
+			t.Errorf("%#v", stats)
+		}
+		if stats.HitRate != 2.0/11.0 {
+			t.Errorf("%#v", stats)
+		}
+	})
+}
+
+func TestRightsCacheDisabled(t *testing.T) {
+	cache, err := newRightsCache(&configuration.ConfigStruct{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cache != nil {
+		t.Error("expected disabled cache")
+	}
+	_, err = newRightsCache(&configuration.ConfigStruct{RightsCacheSize: 10, RightsCacheTtl: "foo"})
+	if err == nil {
+		t.Error("expected ttl error")
+	}
+}
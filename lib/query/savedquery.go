@@ -0,0 +1,64 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/configuration"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+)
+
+// SavedQuery executes the saved query name of kind with the params and paging of request
+// the result is the same as the result of Query() with the resulting QueryFind
+func (this *Query) SavedQuery(tokenStr string, kind string, name string, request model.SavedQueryRequest) (result interface{}, code int, err error) {
+	template, ok := this.savedQueries[kind][name]
+	if !ok {
+		return result, http.StatusNotFound, fmt.Errorf("%w: unknown saved query %v for resource %v", model.ErrNotFound, name, kind)
+	}
+	find := request.Apply(template)
+	return this.Query(tokenStr, model.QueryMessage{Resource: kind, Find: &find})
+}
+
+// getSavedQueries decodes the 'saved_queries' config
+// unknown resources and unknown fields are rejected to detect typos on startup
+func getSavedQueries(config configuration.Config) (result map[string]map[string]model.QueryFind, err error) {
+	result = map[string]map[string]model.QueryFind{}
+	for kind, queries := range config.SavedQueries {
+		if _, ok := config.Resources[kind]; !ok {
+			return result, fmt.Errorf("saved_queries references unknown resource %v", kind)
+		}
+		result[kind] = map[string]model.QueryFind{}
+		for name, raw := range queries {
+			find := model.QueryFind{}
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+			err = decoder.Decode(&find)
+			if err != nil {
+				return result, fmt.Errorf("invalid saved query %v of resource %v: %w", name, kind, err)
+			}
+			err = find.ValidateSearchOptions()
+			if err != nil {
+				return result, fmt.Errorf("invalid saved query %v of resource %v: %w", name, kind, err)
+			}
+			result[kind][name] = find
+		}
+	}
+	return result, nil
+}
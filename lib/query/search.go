@@ -0,0 +1,236 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"strconv"
+	"strings"
+)
+
+const defaultFuzziness = "AUTO"
+
+// searchOptions contains the options of a text search, additional to the search text
+type searchOptions struct {
+	Highlight bool
+	Mode      model.SearchMode //empty -> search_mode of the resource config
+	Fuzziness string           //empty -> fuzziness of the resource config
+	Fields    []string         //optional; restricts the search to these fields; each field may have a boost like 'name^3'
+}
+
+// searchField is a validated element of searchOptions.Fields
+type searchField struct {
+	featureSearchSource
+	Boost float64 //0 -> no boost
+}
+
+// getSearchQuery returns the query for a text search on kind
+// the mode is taken from options or, if not set, from the resource config
+func (this *Query) getSearchQuery(kind string, search string, options searchOptions) (map[string]interface{}, error) {
+	fields, err := this.getSearchFields(kind, options.Fields)
+	if err != nil {
+		return nil, err
+	}
+	resourceConfig := this.config.Resources[kind]
+	mode := options.Mode
+	if mode == "" {
+		mode = resourceConfig.SearchMode
+	}
+	switch mode {
+	case "", model.SearchModeDefault:
+		return this.getFieldsSearchQuery(kind, fields, func(field string) map[string]interface{} {
+			operation, config := this.getFeatureSearchInfoForField(field, search)
+			return map[string]interface{}{operation: config}
+		})
+	case model.SearchModeFuzzy:
+		fuzziness := options.Fuzziness
+		if fuzziness == "" {
+			fuzziness = resourceConfig.Fuzziness
+		}
+		if fuzziness == "" {
+			fuzziness = defaultFuzziness
+		}
+		return this.getFieldsSearchQuery(kind, fields, func(field string) map[string]interface{} {
+			return map[string]interface{}{
+				"match": map[string]interface{}{
+					field: map[string]interface{}{"operator": "AND", "query": search, "fuzziness": fuzziness},
+				},
+			}
+		})
+	case model.SearchModePhrase:
+		return this.getFieldsSearchQuery(kind, fields, func(field string) map[string]interface{} {
+			return map[string]interface{}{
+				"match_phrase": map[string]interface{}{
+					field: map[string]interface{}{"query": search},
+				},
+			}
+		})
+	case model.SearchModeExact:
+		return this.getSourceFieldSearchQuery(kind, search, mode, fields, "term", "match_phrase")
+	case model.SearchModePrefix:
+		return this.getSourceFieldSearchQuery(kind, search, mode, fields, "prefix", "match_phrase_prefix")
+	default:
+		return nil, fmt.Errorf("%w: unknown search_mode '%v' (expected one of %v)", model.ErrBadRequest, mode, model.SearchModes)
+	}
+}
+
+// getFieldsSearchQuery uses query on feature_search or, if fields are given, on the FeatureSearchSubField of each field
+// without fields, the search_boosts of the resource config only influence the score but not which resources match
+func (this *Query) getFieldsSearchQuery(kind string, fields []searchField, query func(field string) map[string]interface{}) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		result := query("feature_search")
+		boosted := []map[string]interface{}{}
+		for _, source := range this.getFeatureSearchSources(kind) {
+			if boost := this.getConfiguredSearchBoost(kind, source.Path); boost > 0 {
+				field := source.Path + "." + model.FeatureSearchSubField
+				err := this.checkSubField(kind, field)
+				if err != nil {
+					return nil, err
+				}
+				boosted = append(boosted, withBoost(query(field), boost))
+			}
+		}
+		if len(boosted) == 0 {
+			return result, nil
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   []map[string]interface{}{result},
+				"should": boosted,
+			},
+		}, nil
+	}
+	should := []map[string]interface{}{}
+	for _, field := range fields {
+		subField := field.Path + "." + model.FeatureSearchSubField
+		err := this.checkSubField(kind, subField)
+		if err != nil {
+			return nil, err
+		}
+		should = append(should, withBoost(query(subField), field.Boost))
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}, nil
+}
+
+func withBoost(query map[string]interface{}, boost float64) map[string]interface{} {
+	if boost <= 0 {
+		return query
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":  []map[string]interface{}{query},
+			"boost": boost,
+		},
+	}
+}
+
+// getSearchFields validates fields against the fields of kind that are copied to feature_search
+// fields may be given without the 'features.' prefix and with a boost (e.g. 'name^3')
+func (this *Query) getSearchFields(kind string, fields []string) (result []searchField, err error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	sources := this.getFeatureSearchSources(kind)
+	for _, field := range fields {
+		name, boostStr, hasBoost := strings.Cut(strings.TrimSpace(field), "^")
+		if !strings.HasPrefix(name, "features.") && !strings.HasPrefix(name, "annotations.") {
+			name = "features." + name
+		}
+		element := searchField{Boost: this.getConfiguredSearchBoost(kind, name)}
+		if hasBoost {
+			element.Boost, err = strconv.ParseFloat(boostStr, 64)
+			if err != nil || element.Boost <= 0 {
+				return nil, fmt.Errorf("%w: invalid boost in search_fields (%v)", model.ErrBadRequest, field)
+			}
+		}
+		found := false
+		for _, source := range sources {
+			if source.Path == name {
+				element.featureSearchSource = source
+				found = true
+				break
+			}
+		}
+		if !found {
+			allowed := []string{}
+			for _, source := range sources {
+				allowed = append(allowed, strings.TrimPrefix(source.Path, "features."))
+			}
+			return nil, fmt.Errorf("%w: search_fields may only contain fields with 'copy_to': 'feature_search' in index_type_mapping of %v (found '%v', expected one of %v)", model.ErrBadRequest, kind, field, allowed)
+		}
+		result = append(result, element)
+	}
+	return result, nil
+}
+
+// getSourceFieldSearchQuery searches the fields that are copied to feature_search instead of the analyzed feature_search field,
+// to be able to match complete values or value prefixes
+// keyword fields use keywordQueryType with case_insensitive, text fields use textQueryType
+// if fields is empty, all fields that are copied to feature_search are used
+func (this *Query) getSourceFieldSearchQuery(kind string, search string, mode model.SearchMode, fields []searchField, keywordQueryType string, textQueryType string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		for _, source := range this.getFeatureSearchSources(kind) {
+			fields = append(fields, searchField{featureSearchSource: source, Boost: this.getConfiguredSearchBoost(kind, source.Path)})
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: search_mode '%v' needs fields with 'copy_to': 'feature_search' in index_type_mapping of %v", model.ErrBadRequest, mode, kind)
+	}
+	should := []map[string]interface{}{}
+	for _, field := range fields {
+		var query map[string]interface{}
+		if field.Type == "text" {
+			query = map[string]interface{}{
+				textQueryType: map[string]interface{}{
+					field.Path: map[string]interface{}{"query": search},
+				},
+			}
+		} else {
+			query = map[string]interface{}{
+				keywordQueryType: map[string]interface{}{
+					field.Path: map[string]interface{}{"value": search, "case_insensitive": true},
+				},
+			}
+		}
+		should = append(should, withBoost(query, field.Boost))
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}, nil
+}
+
+// getConfiguredSearchBoost returns the boost of path in the search_boosts of the resource config or 0 if none is configured
+func (this *Query) getConfiguredSearchBoost(kind string, path string) float64 {
+	for field, boost := range this.config.Resources[kind].SearchBoosts {
+		if !strings.HasPrefix(field, "features.") && !strings.HasPrefix(field, "annotations.") {
+			field = "features." + field
+		}
+		if field == path {
+			return boost
+		}
+	}
+	return 0
+}
@@ -17,19 +17,30 @@
 package query
 
 import (
-	"errors"
+	"fmt"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"strconv"
 
 	"reflect"
 	"strings"
+	"unicode/utf8"
 )
 
-func (this *Query) GetFilter(token auth.Token, selection model.Selection) (result map[string]interface{}, err error) {
+func (this *Query) GetFilter(token auth.Token, kind string, selection model.Selection, params map[string]interface{}) (result map[string]interface{}, err error) {
+	return this.getFilter(token, kind, selection, params, 0)
+}
+
+func (this *Query) GetConditionFilter(token auth.Token, kind string, condition model.ConditionConfig, params map[string]interface{}) (map[string]interface{}, error) {
+	return this.getConditionFilter(token, kind, condition, params, 0)
+}
+
+// getFilter builds the filter of selection; depth counts the 'in_resource_query' conditions the selection is nested in
+func (this *Query) getFilter(token auth.Token, kind string, selection model.Selection, params map[string]interface{}, depth int) (result map[string]interface{}, err error) {
 	if len(selection.And) > 0 {
 		and := []map[string]interface{}{}
 		for _, sub := range selection.And {
-			andElement, err := this.GetFilter(token, sub)
+			andElement, err := this.getFilter(token, kind, sub, params, depth)
 			if err != nil {
 				return result, err
 			}
@@ -45,7 +56,7 @@ func (this *Query) GetFilter(token auth.Token, selection model.Selection) (resul
 	if len(selection.Or) > 0 {
 		or := []map[string]interface{}{}
 		for _, sub := range selection.Or {
-			orElement, err := this.GetFilter(token, sub)
+			orElement, err := this.getFilter(token, kind, sub, params, depth)
 			if err != nil {
 				return result, err
 			}
@@ -59,7 +70,7 @@ func (this *Query) GetFilter(token auth.Token, selection model.Selection) (resul
 		return
 	}
 	if selection.Not != nil {
-		not, err := this.GetFilter(token, *selection.Not)
+		not, err := this.getFilter(token, kind, *selection.Not, params, depth)
 		if err != nil {
 			return result, err
 		}
@@ -70,20 +81,36 @@ func (this *Query) GetFilter(token auth.Token, selection model.Selection) (resul
 		}
 		return result, err
 	}
-	return this.GetConditionFilter(token, selection.Condition)
+	if selection.Nested != nil {
+		fieldType, _ := this.getMappingType(kind, selection.Nested.Path)
+		if fieldType != "nested" {
+			return result, fmt.Errorf("%w: nested selection expects a path with the type 'nested' in index_type_mapping (%v)", model.ErrBadRequest, selection.Nested.Path)
+		}
+		sub, err := this.getFilter(token, kind, selection.Nested.Selection, params, depth)
+		if err != nil {
+			return result, err
+		}
+		result = map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path":  selection.Nested.Path,
+				"query": sub,
+			},
+		}
+		return result, err
+	}
+	return this.getConditionFilter(token, kind, selection.Condition, params, depth)
 }
 
-func (this *Query) GetConditionFilter(token auth.Token, condition model.ConditionConfig) (map[string]interface{}, error) {
+func (this *Query) getConditionFilter(token auth.Token, kind string, condition model.ConditionConfig, params map[string]interface{}, depth int) (map[string]interface{}, error) {
 	if condition.Feature == "id" {
 		condition.Feature = "_id"
 	}
 	val := condition.Value
-	if val == nil || val == "" {
-		switch condition.Ref {
-		case "jwt.user":
-			val = token.GetUserId()
-		case "jwt.groups":
-			val = token.GetRoles()
+	if (val == nil || val == "") && condition.Ref != "" {
+		var err error
+		val, err = resolveRef(token, condition.Ref, params)
+		if err != nil {
+			return nil, err
 		}
 	}
 	switch condition.Operation {
@@ -138,6 +165,247 @@ func (this *Query) GetConditionFilter(token auth.Token, condition model.Conditio
 				condition.Feature: val,
 			},
 		}, nil
+	case model.QueryGreaterOperation:
+		return this.getRangeFilter(kind, condition.Feature, map[string]interface{}{"gt": val})
+	case model.QueryGreaterEqualOperation:
+		return this.getRangeFilter(kind, condition.Feature, map[string]interface{}{"gte": val})
+	case model.QueryLessOperation:
+		return this.getRangeFilter(kind, condition.Feature, map[string]interface{}{"lt": val})
+	case model.QueryLessEqualOperation:
+		return this.getRangeFilter(kind, condition.Feature, map[string]interface{}{"lte": val})
+	case model.QueryBetweenOperation:
+		list, ok := toInterfaceList(val)
+		if !ok || len(list) != 2 {
+			return nil, fmt.Errorf("%w: operation '%v' expects a list of two values [from, to] (found %#v)", model.ErrBadRequest, condition.Operation, val)
+		}
+		if list[0] == nil && list[1] == nil {
+			return nil, fmt.Errorf("%w: operation '%v' expects at least one bound", model.ErrBadRequest, condition.Operation)
+		}
+		bounds := map[string]interface{}{}
+		if list[0] != nil {
+			bounds["gte"] = list[0]
+		}
+		if list[1] != nil {
+			bounds["lte"] = list[1]
+		}
+		return this.getRangeFilter(kind, condition.Feature, bounds)
+	case model.QueryEqualCaseInsensitiveOperation:
+		return this.getTextPatternFilter("term", condition, val)
+	case model.QueryPrefixOperation:
+		return this.getTextPatternFilter("prefix", condition, val)
+	case model.QueryWildcardOperation:
+		return this.getTextPatternFilter("wildcard", condition, val)
+	case model.QueryRegexpOperation:
+		return this.getTextPatternFilter("regexp", condition, val)
+	case model.QueryInResourceQueryOperation:
+		return this.getInResourceQueryFilter(token, condition.Feature, val, params, depth)
+	}
+	return nil, fmt.Errorf("%w: unknown query operation type %v", model.ErrBadRequest, condition.Operation)
+}
+
+// resolveRef returns the value referenced by ref
+// valid refs are 'jwt.user', 'jwt.groups', 'jwt.claim.<name>' and 'param.<name>'
+func resolveRef(token auth.Token, ref string, params map[string]interface{}) (interface{}, error) {
+	switch {
+	case ref == "jwt.user":
+		return token.GetUserId(), nil
+	case ref == "jwt.groups":
+		return token.GetRoles(), nil
+	case strings.HasPrefix(ref, "jwt.claim."):
+		name := strings.TrimPrefix(ref, "jwt.claim.")
+		claims, err := auth.GetClaims(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read claims of token: %v", model.ErrBadRequest, err.Error())
+		}
+		value, ok := claims[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: ref %v references missing token claim", model.ErrBadRequest, ref)
+		}
+		return value, nil
+	case strings.HasPrefix(ref, "param."):
+		name := strings.TrimPrefix(ref, "param.")
+		value, ok := params[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: ref %v references missing param", model.ErrBadRequest, ref)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown ref %v (expected 'jwt.user', 'jwt.groups', 'jwt.claim.<name>' or 'param.<name>')", model.ErrBadRequest, ref)
+	}
+}
+
+// getRangeFilter validates the bounds against the index_type_mapping of the feature
+// to return a bad request error instead of letting OpenSearch fail on an unsupported field type
+func (this *Query) getRangeFilter(kind string, feature string, bounds map[string]interface{}) (map[string]interface{}, error) {
+	if feature == "_id" {
+		feature = "resource"
+	}
+	fieldType, found := this.getMappingType(kind, feature)
+	if !found {
+		return nil, fmt.Errorf("%w: range operations need a feature with a type described in index_type_mapping (%v)", model.ErrBadRequest, feature)
+	}
+	for _, value := range bounds {
+		switch {
+		case isNumericMappingType(fieldType):
+			if !isNumericValue(value) {
+				return nil, fmt.Errorf("%w: range operation on %v (%v) expects numeric value but got %#v", model.ErrBadRequest, feature, fieldType, value)
+			}
+		case fieldType == "date" || fieldType == "date_nanos":
+			if _, isString := value.(string); !isString && !isNumericValue(value) {
+				return nil, fmt.Errorf("%w: range operation on %v (%v) expects date string or epoch millis but got %#v", model.ErrBadRequest, feature, fieldType, value)
+			}
+		case fieldType == "keyword":
+			if _, isString := value.(string); !isString {
+				return nil, fmt.Errorf("%w: range operation on %v (%v) expects string value but got %#v", model.ErrBadRequest, feature, fieldType, value)
+			}
+		default:
+			return nil, fmt.Errorf("%w: range operations are not supported for %v with type %v", model.ErrBadRequest, feature, fieldType)
+		}
+	}
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			feature: bounds,
+		},
+	}, nil
+}
+
+// getTextPatternFilter creates a case-insensitive term, prefix, wildcard or regexp query
+// leading wildcards force OpenSearch to scan every term of the field and are only allowed if config.AllowExpensiveSelectionPatterns is set
+// long patterns and patterns with many quantifiers are always rejected (see getPatternLimitViolation)
+func (this *Query) getTextPatternFilter(queryType string, condition model.ConditionConfig, val interface{}) (map[string]interface{}, error) {
+	pattern, ok := val.(string)
+	if !ok || pattern == "" {
+		return nil, fmt.Errorf("%w: operation '%v' expects a non empty string value (found %#v)", model.ErrBadRequest, condition.Operation, val)
+	}
+	if violation := getPatternLimitViolation(queryType, pattern, this.config.SelectionPatternMaxLength, this.config.SelectionPatternMaxQuantifiers); violation != "" {
+		return nil, fmt.Errorf("%w: operation '%v' does not allow %v (%v)", model.ErrBadRequest, condition.Operation, violation, pattern)
+	}
+	if !this.config.AllowExpensiveSelectionPatterns && isExpensivePattern(queryType, pattern) {
+		return nil, fmt.Errorf("%w: operation '%v' does not allow patterns with leading wildcards (%v)", model.ErrBadRequest, condition.Operation, pattern)
+	}
+	feature := condition.Feature
+	if feature == "_id" && queryType != "term" {
+		feature = "resource"
+	}
+	return map[string]interface{}{
+		queryType: map[string]interface{}{
+			feature: map[string]interface{}{
+				"value":            pattern,
+				"case_insensitive": queryType == "term",
+			},
+		},
+	}, nil
+}
+
+// getPatternLimitViolation returns a description of the exceeded limit of a wildcard or regexp pattern or "" if pattern is within the limits
+// the cost of wildcard and regexp queries grows with the pattern length and the count of quantifiers;
+// index.max_regex_length of OpenSearch only limits regexp patterns and defaults to 1000 characters
+// limits <= 0 are not checked
+func getPatternLimitViolation(queryType string, pattern string, maxLength int64, maxQuantifiers int64) string {
+	if queryType != "wildcard" && queryType != "regexp" {
+		return ""
+	}
+	if maxLength > 0 && int64(utf8.RuneCountInString(pattern)) > maxLength {
+		return fmt.Sprintf("patterns longer than %v characters", maxLength)
+	}
+	if maxQuantifiers > 0 && int64(countPatternQuantifiers(queryType, pattern)) > maxQuantifiers {
+		return fmt.Sprintf("patterns with more than %v quantifiers", maxQuantifiers)
+	}
+	return ""
+}
+
+// countPatternQuantifiers counts the unescaped wildcards (*, ?) of a wildcard pattern
+// or the unescaped quantifiers (*, +, ?, {n,m}) outside of character classes of a regexp pattern; a lazy '*?' counts twice
+func countPatternQuantifiers(queryType string, pattern string) (count int) {
+	quantifiers := "*?"
+	if queryType == "regexp" {
+		quantifiers = "*+?{"
+	}
+	inClass := false
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\':
+			i++
+		case queryType == "regexp" && runes[i] == '[':
+			inClass = true
+		case inClass && runes[i] == ']':
+			inClass = false
+		case !inClass && strings.ContainsRune(quantifiers, runes[i]):
+			count++
+		}
+	}
+	return count
+}
+
+// isExpensivePattern checks for leading wildcards; for regexp patterns every top level alternative ('|') is checked
+// a leading character class, group, any-string or complement forces a scan of all terms like a leading '.'
+func isExpensivePattern(queryType string, pattern string) bool {
+	switch queryType {
+	case "wildcard":
+		return strings.HasPrefix(pattern, "*") || strings.HasPrefix(pattern, "?")
+	case "regexp":
+		for _, alternative := range getRegexpAlternatives(pattern) {
+			if alternative == "" || strings.ContainsRune(".([@~<", []rune(alternative)[0]) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// getRegexpAlternatives splits pattern at '|' and '&' outside of groups, character classes and escapes
+func getRegexpAlternatives(pattern string) (result []string) {
+	runes := []rune(pattern)
+	depth := 0
+	inClass := false
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\':
+			i++
+		case inClass:
+			if runes[i] == ']' {
+				inClass = false
+			}
+		case runes[i] == '[':
+			inClass = true
+		case runes[i] == '(':
+			depth++
+		case runes[i] == ')':
+			depth--
+		case depth == 0 && (runes[i] == '|' || runes[i] == '&'):
+			result = append(result, string(runes[start:i]))
+			start = i + 1
+		}
+	}
+	return append(result, string(runes[start:]))
+}
+
+func toInterfaceList(value interface{}) (result []interface{}, ok bool) {
+	if value == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	for i := 0; i < v.Len(); i++ {
+		result = append(result, v.Index(i).Interface())
+	}
+	return result, true
+}
+
+func isNumericValue(value interface{}) bool {
+	switch v := value.(type) {
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	default:
+		return false
 	}
-	return nil, errors.New("unknown query operation type " + string(condition.Operation))
 }
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"strings"
+	"testing"
+)
+
+func TestPatternLimits(t *testing.T) {
+	for _, c := range []struct {
+		queryType   string
+		pattern     string
+		quantifiers int
+		expensive   bool
+	}{
+		{queryType: "wildcard", pattern: "lab-?", quantifiers: 1},
+		{queryType: "wildcard", pattern: `lab-\*`, quantifiers: 0},
+		{queryType: "wildcard", pattern: "*lab", quantifiers: 1, expensive: true},
+		{queryType: "wildcard", pattern: "?lab", quantifiers: 1, expensive: true},
+		{queryType: "regexp", pattern: "lab-[0-9]{1,3}", quantifiers: 1},
+		{queryType: "regexp", pattern: "lab-[*+?]", quantifiers: 0},
+		{queryType: "regexp", pattern: "lab.*?x+", quantifiers: 3},
+		{queryType: "regexp", pattern: `lab\.\*`, quantifiers: 0},
+		{queryType: "regexp", pattern: "lab(1|.*x)", quantifiers: 1},
+		{queryType: "regexp", pattern: ".*lab", quantifiers: 1, expensive: true},
+		{queryType: "regexp", pattern: "[lL]ab", quantifiers: 0, expensive: true},
+		{queryType: "regexp", pattern: "(lab)", quantifiers: 0, expensive: true},
+		{queryType: "regexp", pattern: "abc|.*x", quantifiers: 1, expensive: true},
+		{queryType: "regexp", pattern: "abc|", quantifiers: 0, expensive: true},
+		{queryType: "regexp", pattern: `abc\|.*x`, quantifiers: 1},
+		{queryType: "regexp", pattern: "a[|]b", quantifiers: 0},
+	} {
+		if count := countPatternQuantifiers(c.queryType, c.pattern); count != c.quantifiers {
+			t.Error(c.queryType, c.pattern, "quantifiers", count, c.quantifiers)
+		}
+		if expensive := isExpensivePattern(c.queryType, c.pattern); expensive != c.expensive {
+			t.Error(c.queryType, c.pattern, "expensive", expensive, c.expensive)
+		}
+	}
+	if violation := getPatternLimitViolation("regexp", "a?b{2}c*d+", 256, 3); violation == "" {
+		t.Error("expected quantifier violation")
+	}
+	if violation := getPatternLimitViolation("wildcard", "lab"+strings.Repeat("x", 300), 256, 5); violation == "" {
+		t.Error("expected length violation")
+	}
+	if violation := getPatternLimitViolation("wildcard", "lab"+strings.Repeat("?", 300), 0, 0); violation != "" {
+		t.Error(violation)
+	}
+}
+
+func TestInResourceQueryDepth(t *testing.T) {
+	condition := model.ConditionConfig{
+		Feature:   "features.device_type_id",
+		Operation: model.QueryInResourceQueryOperation,
+		Value:     model.ResourceQuery{Resource: "device-types"},
+	}
+	_, err := (&Query{}).getConditionFilter(auth.Token{}, "devices", condition, nil, maxInResourceQueryDepth)
+	if !errors.Is(err, model.ErrBadRequest) {
+		t.Error(err)
+	}
+}
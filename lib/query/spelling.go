@@ -0,0 +1,135 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxSpellingSuggestions = 5
+
+// getSpellingSuggestions returns corrections of search, computed by a phrase suggester for each searchable field
+// the suggesters use the terms of all resources, but a collate query with the rights filter removes every correction
+// that matches no resource with the requested rights, so that no values of other resources leak
+func (this *Query) getSpellingSuggestions(token auth.Token, kind string, search string, fields []string, rights string) (result []string, err error) {
+	result = []string{}
+	searchFields, err := this.getSearchFields(kind, fields)
+	if err != nil {
+		return result, err
+	}
+	sources := []featureSearchSource{}
+	for _, field := range searchFields {
+		sources = append(sources, field.featureSearchSource)
+	}
+	if len(sources) == 0 {
+		sources = this.getFeatureSearchSources(kind)
+	}
+	if len(sources) == 0 {
+		return result, nil
+	}
+	suggest := map[string]interface{}{"text": search}
+	for i, source := range sources {
+		field := source.Path + "." + model.FeatureSpellSubField
+		err = this.checkSubField(kind, field)
+		if err != nil {
+			return result, err
+		}
+		filter := getRightsQuery(rights, token.GetUserId(), token.GetRoles())
+		filter = append(filter, map[string]interface{}{
+			"match": map[string]interface{}{
+				field: map[string]interface{}{"query": "{{suggestion}}", "operator": "and"},
+			},
+		})
+		suggest[getSpellingSuggesterName(i)] = map[string]interface{}{
+			"phrase": map[string]interface{}{
+				"field":      field,
+				"size":       maxSpellingSuggestions,
+				"max_errors": 2,
+				"confidence": 0,
+				"direct_generator": []map[string]interface{}{{
+					"field":        field,
+					"suggest_mode": "always",
+				}},
+				"collate": map[string]interface{}{
+					"query": map[string]interface{}{
+						"source": map[string]interface{}{
+							"bool": map[string]interface{}{"filter": filter},
+						},
+					},
+				},
+			},
+		}
+	}
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(this.getTimeout()),
+		this.opensearchClient.Search.WithIndex(kind),
+		this.opensearchClient.Search.WithSize(0),
+		this.opensearchClient.Search.WithTrackTotalHits(false),
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(map[string]interface{}{"suggest": suggest})),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := struct {
+		Suggest map[string][]struct {
+			Options []spellingSuggestion `json:"options"`
+		} `json:"suggest"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	candidates := []spellingSuggestion{}
+	for i := range sources {
+		for _, entry := range pl.Suggest[getSpellingSuggesterName(i)] {
+			candidates = append(candidates, entry.Options...)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	for _, candidate := range candidates {
+		if len(result) >= maxSpellingSuggestions {
+			break
+		}
+		if strings.EqualFold(candidate.Text, search) || contains(result, candidate.Text) {
+			continue
+		}
+		result = append(result, candidate.Text)
+	}
+	return result, nil
+}
+
+type spellingSuggestion struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+func getSpellingSuggesterName(index int) string {
+	return "spelling_" + strconv.Itoa(index)
+}
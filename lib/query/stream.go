@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
+	"github.com/SENERGY-Platform/permission-search/lib/query/modifier"
+)
+
+const streamBatchSize = 1000
+
+// Stream calls handler with batches of all resources List would return for options
+// limit, offset, sort, after and with_cursor are ignored: the resources are sorted by id and read with search_after
+// from a point in time snapshot, so that the result is never buffered completely and concurrent writes do not skip
+// or duplicate resources; an error of handler or the cancellation of ctx stops the stream
+func (this *Query) Stream(ctx context.Context, tokenStr string, kind string, options model.ListOptions, handler func(batch []map[string]interface{}) error) error {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return err
+	}
+	err = options.ValidateSearchOptions()
+	if err != nil {
+		return err
+	}
+	if options.Highlight || options.DidYouMean {
+		return fmt.Errorf("%w: 'highlight' and 'did_you_mean' are not supported by streams", model.ErrBadRequest)
+	}
+	pureIds, preparedModify := this.modifier.PrepareListModify(options.ListIds)
+	body, err := this.getListOptionsBody(token, kind, options, pureIds)
+	if err != nil {
+		return err
+	}
+	query, _ := body["query"].(map[string]interface{})
+	sourceFilter := this.getSourceFilter(kind, options.QueryListCommons)
+	modifyCache := modifier.NewModifyResourceReferenceCache()
+	return opensearchclient.IterateEntries(ctx, this.opensearchClient, kind, query, streamBatchSize, func(entries []model.Entry) error {
+		batch := []map[string]interface{}{}
+		for _, entry := range entries {
+			modified := []model.Entry{entry}
+			if len(options.ListIds) > 0 {
+				modified, err = this.modifier.UsePreparedModify(preparedModify, entry, kind, modifyCache)
+				if err != nil {
+					return err
+				}
+			}
+			for _, element := range modified {
+				batch = append(batch, getEntryResult(projectEntry(element, sourceFilter), token.GetUserId(), token.GetRoles()))
+			}
+		}
+		if len(options.AddIdModifier) > 0 {
+			batch, err, _ = this.addParsedModifier(token, kind, batch, options.AddIdModifier, options.QueryListCommons)
+			if err != nil {
+				return err
+			}
+		}
+		return handler(batch)
+	})
+}
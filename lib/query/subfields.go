@@ -0,0 +1,90 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
+	"log"
+	"sort"
+)
+
+// loadMissingSubFields compares the multi-fields of the expected mapping of every resource with the live index
+// indexes created before a multi-field (e.g. FeatureSearchSubField) was added to the mapping have no values in it,
+// so searches on it would silently find nothing; such fields are logged and rejected by checkSubField
+func (this *Query) loadMissingSubFields(ctx context.Context) (err error) {
+	this.missingSubFields = map[string]map[string]bool{}
+	for _, kind := range this.config.ResourceList {
+		expected, err := model.CreateMapping(this.config, kind)
+		if err != nil {
+			return err
+		}
+		live, err := opensearchclient.GetIndexProperties(this.opensearchClient, ctx, kind)
+		if err != nil {
+			return err
+		}
+		expectedProperties, _ := expected["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+		missing := getMissingSubFields(expectedProperties, live, "")
+		if len(missing) > 0 {
+			log.Printf("ERROR: the index of %v has no sub fields %v; searches using them fail until the index is updated with the 'update-indexes' command\n", kind, missing)
+			this.missingSubFields[kind] = map[string]bool{}
+			for _, field := range missing {
+				this.missingSubFields[kind][field] = true
+			}
+		}
+	}
+	return nil
+}
+
+// getMissingSubFields returns the paths of the multi-fields of expected that are not part of live, sorted
+func getMissingSubFields(expected map[string]interface{}, live map[string]interface{}, prefix string) (result []string) {
+	for name, value := range expected {
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		liveField, _ := live[name].(map[string]interface{})
+		if sub, ok := field["properties"].(map[string]interface{}); ok {
+			liveSub, _ := liveField["properties"].(map[string]interface{})
+			result = append(result, getMissingSubFields(sub, liveSub, prefix+name+".")...)
+		}
+		if liveField == nil {
+			//unknown fields are added by dynamic mapping or by try_mapping_update_on_startup and have no old values
+			continue
+		}
+		if subFields, ok := field["fields"].(map[string]interface{}); ok {
+			liveSubFields, _ := liveField["fields"].(map[string]interface{})
+			for subName := range subFields {
+				if _, ok := liveSubFields[subName]; !ok {
+					result = append(result, prefix+name+"."+subName)
+				}
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// checkSubField returns an error if field is a missing multi-field of the index of kind (see loadMissingSubFields)
+func (this *Query) checkSubField(kind string, field string) error {
+	if this.missingSubFields[kind][field] {
+		return fmt.Errorf("the index of %v has no sub field %v; it has to be updated with the 'update-indexes' command", kind, field)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetMissingSubFields(t *testing.T) {
+	expected := map[string]interface{}{
+		"resource": map[string]interface{}{"type": "keyword"},
+		"features": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":   "keyword",
+					"fields": map[string]interface{}{"search": map[string]interface{}{"type": "text"}, "spell": map[string]interface{}{"type": "text"}},
+				},
+				"description": map[string]interface{}{
+					"type":   "text",
+					"fields": map[string]interface{}{"search": map[string]interface{}{"type": "text"}},
+				},
+				"new_field": map[string]interface{}{
+					"type":   "keyword",
+					"fields": map[string]interface{}{"search": map[string]interface{}{"type": "text"}},
+				},
+			},
+		},
+	}
+	live := map[string]interface{}{
+		"resource": map[string]interface{}{"type": "keyword"},
+		"features": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":   "keyword",
+					"fields": map[string]interface{}{"search": map[string]interface{}{"type": "text"}},
+				},
+				"description": map[string]interface{}{"type": "text"},
+			},
+		},
+	}
+	actual := getMissingSubFields(expected, live, "")
+	if !reflect.DeepEqual(actual, []string{"features.description.search", "features.name.spell"}) {
+		t.Error(actual)
+	}
+}
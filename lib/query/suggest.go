@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/auth"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	"strings"
+)
+
+// Suggest returns resources with a feature that matches the start of the words of options.Query, ranked by relevance
+// only features with 'Suggest' in the resource config are used
+func (this *Query) Suggest(tokenStr string, kind string, options model.SuggestOptions) (result []model.Suggestion, err error) {
+	token, err := auth.Parse(tokenStr)
+	if err != nil {
+		return result, err
+	}
+	err = options.Validate()
+	if err != nil {
+		return result, err
+	}
+	if options.Limit == 0 {
+		options.Limit = 10
+	}
+	if options.Rights == "" {
+		options.Rights = "r"
+	}
+	fields, err := this.getSuggestFields(kind, options.Field)
+	if err != nil {
+		return result, err
+	}
+	matches := []map[string]interface{}{}
+	source := []string{"resource"}
+	for _, field := range fields {
+		subField := "features." + field + "." + model.SuggestSubField
+		err = this.checkSubField(kind, subField)
+		if err != nil {
+			return result, err
+		}
+		matches = append(matches, map[string]interface{}{
+			"match": map[string]interface{}{
+				subField: map[string]interface{}{
+					"query":    options.Query,
+					"operator": "AND",
+					"_name":    field,
+				},
+			},
+		})
+		source = append(source, "features."+field)
+	}
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter":               getRightsQuery(options.Rights, token.GetUserId(), token.GetRoles()),
+				"should":               matches,
+				"minimum_should_match": 1,
+			},
+		},
+		"_source": source,
+		"sort":    getSortBody(model.QueryListCommons{Sort: []model.SortField{{Field: model.SortByScore}}}),
+	}
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(this.getTimeout()),
+		this.opensearchClient.Search.WithIndex(kind),
+		this.opensearchClient.Search.WithSize(options.Limit),
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(query)),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := model.SearchResult[model.Entry]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	result = []model.Suggestion{}
+	for _, hit := range pl.Hits.Hits {
+		result = append(result, model.Suggestion{
+			Id:   hit.Source.Resource,
+			Text: getSuggestionText(hit, fields),
+		})
+	}
+	return result, nil
+}
+
+// getSuggestFields returns the suggest features of kind, or field if it is one of them
+func (this *Query) getSuggestFields(kind string, field string) (result []string, err error) {
+	resourceConfig, ok := this.config.Resources[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown resource %v", model.ErrBadRequest, kind)
+	}
+	suggestFeatures := resourceConfig.SuggestFeatures()
+	if len(suggestFeatures) == 0 {
+		return nil, fmt.Errorf("%w: resource %v has no feature with 'Suggest' in the resource config", model.ErrBadRequest, kind)
+	}
+	if field == "" {
+		return suggestFeatures, nil
+	}
+	field = strings.TrimPrefix(field, "features.")
+	if !contains(suggestFeatures, field) {
+		return nil, fmt.Errorf("%w: field %v has no 'Suggest' in the resource config of %v", model.ErrBadRequest, field, kind)
+	}
+	return []string{field}, nil
+}
+
+// getSuggestionText returns the value of the first field that matched the suggest query
+func getSuggestionText(hit model.Hit[model.Entry], fields []string) string {
+	for _, field := range fields {
+		if contains(hit.MatchedQueries, field) {
+			if value, ok := hit.Source.Features[field]; ok && value != nil {
+				return fmt.Sprint(value)
+			}
+		}
+	}
+	return ""
+}
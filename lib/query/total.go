@@ -22,38 +22,73 @@ import (
 	"errors"
 	"github.com/SENERGY-Platform/permission-search/lib/auth"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/query/modifier"
 	"github.com/opensearch-project/opensearch-go/opensearchutil"
 	"strings"
 )
 
+// Total counts the resources List would return for options
 func (this *Query) Total(tokenStr string, kind string, options model.ListOptions) (result int64, err error) {
 	token, err := auth.Parse(tokenStr)
 	if err != nil {
 		return result, err
 	}
-	mode, err := options.Mode()
+	err = options.ValidateSearchOptions()
 	if err != nil {
 		return result, err
 	}
-	switch mode {
-	case model.ListOptionsModeTextSearch:
-		return this.SearchListTotal(token, kind, options.TextSearch, options.QueryListCommons.Rights)
-	case model.ListOptionsModeSelection:
-		//options.Mode() guaranties that options.Selection is not empty; panic otherwise
-		return this.SelectByFeatureTotal(token, kind, options.Selection.Feature, options.Selection.Value, options.QueryListCommons.Rights)
-	default:
-		return this.GetListTotalForUserOrGroup(token, kind, options.QueryListCommons.Rights)
+	pureIds, preparedModify := this.modifier.PrepareListModify(options.ListIds)
+	body, err := this.getListOptionsBody(token, kind, options, pureIds)
+	if err != nil {
+		return result, err
+	}
+	if len(options.ListIds) > len(pureIds) {
+		return this.getModifiedTotal(kind, body, pureIds, preparedModify)
 	}
+	return this.getTotal(kind, body)
+}
+
+// getModifiedTotal counts like getModifiedListFromIds: every matching resource counts once per requested (modified) id
+func (this *Query) getModifiedTotal(kind string, body map[string]interface{}, pureIds []string, preparedModify map[string][]modifier.PreparedModifyInfo) (result int64, err error) {
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(this.getTimeout()),
+		this.opensearchClient.Search.WithIndex(kind),
+		this.opensearchClient.Search.WithSize(len(pureIds)),
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(map[string]interface{}{"query": body["query"], "_source": false})),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := model.SearchResult[model.Entry]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	for _, hit := range pl.Hits.Hits {
+		result = result + int64(len(preparedModify[hit.Id]))
+	}
+	return result, nil
 }
 
 func (this *Query) SearchListTotal(token auth.Token, kind string, query string, rights string) (result int64, err error) {
+	return this.searchListTotal(token, kind, query, searchOptions{}, rights)
+}
+
+func (this *Query) searchListTotal(token auth.Token, kind string, query string, options searchOptions, rights string) (result int64, err error) {
 	filter := getRightsQuery(rights, token.GetUserId(), token.GetRoles())
-	searchOperation, searchConfig := this.getFeatureSearchInfo(query)
+	searchQuery, err := this.getSearchQuery(kind, query, options)
+	if err != nil {
+		return result, err
+	}
 	body := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
 				"filter": filter,
-				"must":   []map[string]interface{}{{searchOperation: searchConfig}},
+				"must":   []map[string]interface{}{searchQuery},
 			},
 		},
 	}
@@ -121,6 +156,30 @@ func (this *Query) SelectByFeatureTotal(token auth.Token, kind string, field str
 	return pl.Hits.Total.Value, nil
 }
 
+// getTotal counts the resources matching the query of body
+func (this *Query) getTotal(kind string, body map[string]interface{}) (result int64, err error) {
+	resp, err := this.opensearchClient.Search(
+		this.opensearchClient.Search.WithContext(this.getTimeout()),
+		this.opensearchClient.Search.WithIndex(kind),
+		this.opensearchClient.Search.WithTrackTotalHits(true),
+		this.opensearchClient.Search.WithSize(0),
+		this.opensearchClient.Search.WithBody(opensearchutil.NewJSONReader(map[string]interface{}{"query": body["query"]})),
+	)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return result, errors.New(resp.String())
+	}
+	pl := model.SearchResult[model.Entry]{}
+	err = json.NewDecoder(resp.Body).Decode(&pl)
+	if err != nil {
+		return result, err
+	}
+	return pl.Hits.Total.Value, nil
+}
+
 func (this *Query) GetListTotalForUserOrGroup(token auth.Token, kind string, rights string) (result int64, err error) {
 	ctx := context.Background()
 	query := map[string]interface{}{
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryExpression(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "d1", "device_type_id": "dt1", "nickname": "n1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "d2", "device_type_id": "dt1"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "d3", "device_type_id": "dt2", "nickname": "n3"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	check := func(expression string, expectedIds []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			options := client.ListOptions{
+				QueryListCommons: client.QueryListCommons{Limit: 10, SortBy: "name"},
+				Expression:       expression,
+			}
+			list, err := c.List(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids := []string{}
+			for _, element := range list {
+				ids = append(ids, element["id"].(string))
+			}
+			if !reflect.DeepEqual(ids, expectedIds) {
+				t.Error(ids, expectedIds)
+			}
+			total, err := c.Total(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if total != int64(len(expectedIds)) {
+				t.Error(total, len(expectedIds))
+			}
+		}
+	}
+
+	t.Run("equal", check(`device_type_id == "dt1"`, []string{"d1", "d2"}))
+	t.Run("and not null", check(`device_type_id == "dt1" and not nickname == null`, []string{"d1"}))
+	t.Run("or", check(`(device_type_id == "dt2" or name == "d2") and id != "d1"`, []string{"d2", "d3"}))
+	t.Run("in", check(`id in ["d1", "d3"]`, []string{"d1", "d3"}))
+	t.Run("prefix", check(`nickname prefix "n"`, []string{"d1", "d3"}))
+
+	t.Run("parse error", func(t *testing.T) {
+		_, err := c.List(testtoken, resource, client.ListOptions{Expression: `name == "d1" and (`})
+		if err == nil || model.GetErrCode(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "position 19") {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/worker"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryInResourceQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "lab"}))
+	t.Run("create dt2", saveTestDeviceType(w, "dt2", map[string]interface{}{"name": "office"}))
+	t.Run("create d1", saveTestDevice(w, "devices", "d1", map[string]interface{}{"name": "d1", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, "devices", "d2", map[string]interface{}{"name": "d2", "device_type_id": "dt2"}))
+	t.Run("create d3", saveTestDevice(w, "devices", "d3", map[string]interface{}{"name": "d3", "device_type_id": "dt1"}))
+
+	time.Sleep(2 * time.Second)
+
+	list := func(value interface{}) ([]map[string]interface{}, error) {
+		return q.GetListWithSelection(
+			createTestToken("testOwner", []string{"user"}),
+			"devices",
+			model.QueryListCommons{
+				Limit:    100,
+				Offset:   0,
+				Rights:   "r",
+				SortBy:   "name",
+				SortDesc: false,
+			},
+			model.Selection{Condition: model.ConditionConfig{
+				Feature:   "features.device_type_id",
+				Operation: model.QueryInResourceQueryOperation,
+				Value:     value,
+			}})
+	}
+
+	labDeviceTypes := model.ResourceQuery{
+		Resource: "device-types",
+		Rights:   "x",
+		Filter: &model.Selection{Condition: model.ConditionConfig{
+			Feature:   "features.name",
+			Operation: model.QueryEqualOperation,
+			Value:     "lab",
+		}},
+	}
+
+	t.Run("in_resource_query", func(t *testing.T) {
+		result, err := list(labDeviceTypes)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		names := []string{}
+		for _, element := range result {
+			names = append(names, element["name"].(string))
+		}
+		if !reflect.DeepEqual(names, []string{"d1", "d3"}) {
+			t.Error(names)
+		}
+	})
+
+	t.Run("json value", func(t *testing.T) {
+		result, err := list(map[string]interface{}{
+			"resource": "device-types",
+			"filter":   map[string]interface{}{"condition": map[string]interface{}{"feature": "features.name", "operation": "==", "value": "office"}},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 || result[0]["name"] != "d2" {
+			t.Error(result)
+		}
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		_, err := list(model.ResourceQuery{Resource: "unknown"})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("nesting depth", func(t *testing.T) {
+		nested := func(resource string, inner model.ResourceQuery) model.ResourceQuery {
+			return model.ResourceQuery{
+				Resource: resource,
+				Filter: &model.Selection{Condition: model.ConditionConfig{
+					Feature:   "features.device_type_id",
+					Operation: model.QueryInResourceQueryOperation,
+					Value:     inner,
+				}},
+			}
+		}
+		_, err := list(nested("devices", labDeviceTypes))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = list(nested("devices", nested("devices", labDeviceTypes)))
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		config.InResourceQueryLimit = 1
+		_, err := list(model.ResourceQuery{Resource: "device-types"})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+}
+
+func saveTestDeviceType(w *worker.Worker, id string, fields map[string]interface{}) func(t *testing.T) {
+	return func(t *testing.T) {
+		msg, cmd := getDtTestObj(id, fields)
+		err := w.UpdateFeatures("device-types", msg, cmd)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
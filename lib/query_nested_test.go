@@ -0,0 +1,129 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryNested(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{
+		"name": "d1",
+		"attributes": []map[string]interface{}{
+			{"key": "shared/nickname", "value": "foo"},
+			{"key": "other", "value": "bar"},
+		},
+	}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{
+		"name": "d2",
+		"attributes": []map[string]interface{}{
+			{"key": "shared/nickname", "value": "bar"},
+			{"key": "other", "value": "foo"},
+		},
+	}))
+
+	time.Sleep(2 * time.Second)
+
+	list := func(filter model.Selection) ([]map[string]interface{}, error) {
+		return q.GetListWithSelection(
+			createTestToken("testOwner", []string{"user"}),
+			resource,
+			model.QueryListCommons{
+				Limit:    100,
+				Offset:   0,
+				Rights:   "r",
+				SortBy:   "name",
+				SortDesc: false,
+			},
+			filter)
+	}
+
+	attributeSelection := model.Selection{And: []model.Selection{
+		{Condition: model.ConditionConfig{Feature: "features.attributes.key", Operation: model.QueryEqualOperation, Value: "shared/nickname"}},
+		{Condition: model.ConditionConfig{Feature: "features.attributes.value", Operation: model.QueryEqualOperation, Value: "foo"}},
+	}}
+	nestedSelection := model.Selection{Nested: &model.NestedSelection{
+		Path:      "features.attributes",
+		Selection: attributeSelection,
+	}}
+
+	checkNames := func(filter model.Selection, expectedNames []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := list(filter)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			names := []string{}
+			for _, element := range result {
+				names = append(names, element["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Error(names, expectedNames)
+			}
+		}
+	}
+
+	t.Run("object mapping matches across list elements", checkNames(attributeSelection, []string{"d1", "d2"}))
+
+	t.Run("nested selection on object mapping", func(t *testing.T) {
+		_, err := list(nestedSelection)
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("update mapping to nested", func(t *testing.T) {
+		config.IndexTypeMapping[resource]["features"]["attributes"] = map[string]interface{}{
+			"type": "nested",
+			"properties": map[string]interface{}{
+				"key":   map[string]interface{}{"type": "keyword"},
+				"value": map[string]interface{}{"type": "keyword"},
+			},
+		}
+		err = opensearchclient.UpdateIndexes(config, resource)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("nested selection", checkNames(nestedSelection, []string{"d1"}))
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryRange(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "a"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "b"}))
+	t.Run("create dg3", saveTestDeviceGroup(w, resource, "dg3", map[string]interface{}{"name": "c"}))
+	t.Run("create dg4", saveTestDeviceGroup(w, resource, "dg4", map[string]interface{}{"name": "d"}))
+	t.Run("create dg5", saveTestDeviceGroup(w, resource, "dg5", map[string]interface{}{"name": "e"}))
+
+	time.Sleep(2 * time.Second)
+
+	list := func(filter model.Selection) ([]map[string]interface{}, error) {
+		return q.GetListWithSelection(
+			createTestToken("testOwner", []string{"user"}),
+			resource,
+			model.QueryListCommons{
+				Limit:    100,
+				Offset:   0,
+				Rights:   "r",
+				SortBy:   "name",
+				SortDesc: false,
+			},
+			filter)
+	}
+
+	check := func(operation model.QueryOperationType, value interface{}, expectedNames []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := list(model.Selection{Condition: model.ConditionConfig{
+				Feature:   "features.name",
+				Operation: operation,
+				Value:     value,
+			}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			names := []string{}
+			for _, element := range result {
+				names = append(names, element["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Error(names, expectedNames)
+			}
+		}
+	}
+
+	t.Run(">", check(model.QueryGreaterOperation, "c", []string{"d", "e"}))
+	t.Run(">=", check(model.QueryGreaterEqualOperation, "c", []string{"c", "d", "e"}))
+	t.Run("<", check(model.QueryLessOperation, "c", []string{"a", "b"}))
+	t.Run("<=", check(model.QueryLessEqualOperation, "c", []string{"a", "b", "c"}))
+	t.Run("between", check(model.QueryBetweenOperation, []string{"b", "d"}, []string{"b", "c", "d"}))
+	t.Run("between open end", check(model.QueryBetweenOperation, []interface{}{"d", nil}, []string{"d", "e"}))
+
+	checkBadRequest := func(condition model.ConditionConfig) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := list(model.Selection{Condition: condition})
+			if !errors.Is(err, model.ErrBadRequest) {
+				t.Error(err)
+			}
+		}
+	}
+
+	t.Run("unknown field", checkBadRequest(model.ConditionConfig{
+		Feature:   "features.unknown",
+		Operation: model.QueryGreaterOperation,
+		Value:     "c",
+	}))
+	t.Run("wrong value type", checkBadRequest(model.ConditionConfig{
+		Feature:   "features.name",
+		Operation: model.QueryGreaterOperation,
+		Value:     42,
+	}))
+	t.Run("between without list", checkBadRequest(model.ConditionConfig{
+		Feature:   "features.name",
+		Operation: model.QueryBetweenOperation,
+		Value:     "c",
+	}))
+	t.Run("object field", checkBadRequest(model.ConditionConfig{
+		Feature:   "features.attributes",
+		Operation: model.QueryLessOperation,
+		Value:     "c",
+	}))
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryRefs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "tenant_a"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "tenant_b"}))
+
+	time.Sleep(2 * time.Second)
+
+	token, err := createTestTokenWithClaims(map[string]interface{}{
+		"sub":          "testOwner",
+		"realm_access": map[string]interface{}{"roles": []string{"user"}},
+		"tenant":       "tenant_a",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	find := func(ref string, params map[string]interface{}) (result interface{}, code int, err error) {
+		return q.Query(token, model.QueryMessage{
+			Resource: resource,
+			Find: &model.QueryFind{
+				QueryListCommons: model.QueryListCommons{SortBy: "name"},
+				Filter: &model.Selection{Condition: model.ConditionConfig{
+					Feature:   "features.name",
+					Operation: model.QueryEqualOperation,
+					Ref:       ref,
+				}},
+				Params: params,
+			},
+		})
+	}
+
+	checkName := func(ref string, params map[string]interface{}, expectedName string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, _, err := find(ref, params)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			list, ok := result.([]map[string]interface{})
+			if !ok || len(list) != 1 || list[0]["name"] != expectedName {
+				t.Errorf("%#v", result)
+			}
+		}
+	}
+
+	checkBadRequest := func(ref string, params map[string]interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, code, err := find(ref, params)
+			if err == nil || code != http.StatusBadRequest {
+				t.Error(code, err)
+			}
+		}
+	}
+
+	t.Run("jwt.claim", checkName("jwt.claim.tenant", nil, "tenant_a"))
+	t.Run("param", checkName("param.name", map[string]interface{}{"name": "tenant_b"}, "tenant_b"))
+	t.Run("missing claim", checkBadRequest("jwt.claim.unknown", nil))
+	t.Run("missing param", checkBadRequest("param.name", nil))
+	t.Run("unknown ref", checkBadRequest("foo.bar", nil))
+}
+
+// createTestTokenWithClaims creates an unsigned jwt string, which is sufficient for the token parsing of the query
+func createTestTokenWithClaims(claims map[string]interface{}) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".c2lnbmF0dXJl", nil
+}
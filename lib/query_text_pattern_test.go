@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryTextPattern(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "lab-1"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "lab-2"}))
+	t.Run("create dg3", saveTestDeviceGroup(w, resource, "dg3", map[string]interface{}{"name": "Lab-3"}))
+	t.Run("create dg4", saveTestDeviceGroup(w, resource, "dg4", map[string]interface{}{"name": "office-lab"}))
+
+	time.Sleep(2 * time.Second)
+
+	list := func(condition model.ConditionConfig) ([]map[string]interface{}, error) {
+		return q.GetListWithSelection(
+			createTestToken("testOwner", []string{"user"}),
+			resource,
+			model.QueryListCommons{
+				Limit:    100,
+				Offset:   0,
+				Rights:   "r",
+				SortBy:   "name",
+				SortDesc: false,
+			},
+			model.Selection{Condition: condition})
+	}
+
+	check := func(operation model.QueryOperationType, value interface{}, expectedNames []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := list(model.ConditionConfig{
+				Feature:   "features.name",
+				Operation: operation,
+				Value:     value,
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			names := []string{}
+			for _, element := range result {
+				names = append(names, element["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Error(names, expectedNames)
+			}
+		}
+	}
+
+	t.Run("prefix", check(model.QueryPrefixOperation, "lab-", []string{"lab-1", "lab-2"}))
+	t.Run("wildcard", check(model.QueryWildcardOperation, "lab-?", []string{"lab-1", "lab-2"}))
+	t.Run("regexp", check(model.QueryRegexpOperation, "lab-[0-9]", []string{"lab-1", "lab-2"}))
+	t.Run("==ci", check(model.QueryEqualCaseInsensitiveOperation, "LAB-3", []string{"Lab-3"}))
+
+	checkBadRequest := func(operation model.QueryOperationType, value interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := list(model.ConditionConfig{
+				Feature:   "features.name",
+				Operation: operation,
+				Value:     value,
+			})
+			if !errors.Is(err, model.ErrBadRequest) {
+				t.Error(err)
+			}
+		}
+	}
+
+	t.Run("leading wildcard", checkBadRequest(model.QueryWildcardOperation, "*lab"))
+	t.Run("leading regexp wildcard", checkBadRequest(model.QueryRegexpOperation, ".*lab"))
+	t.Run("long pattern", checkBadRequest(model.QueryWildcardOperation, "lab"+strings.Repeat("?", 300)))
+	t.Run("many quantifiers", checkBadRequest(model.QueryRegexpOperation, "l{1,2}a?b*-+1*?"))
+	t.Run("leading character class", checkBadRequest(model.QueryRegexpOperation, "[lL]ab-[0-9]"))
+	t.Run("expensive alternative", checkBadRequest(model.QueryRegexpOperation, "lab-1|.*lab"))
+	t.Run("non string prefix", checkBadRequest(model.QueryPrefixOperation, 42))
+}
@@ -18,20 +18,19 @@ package replay
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/SENERGY-Platform/permission-search/lib/configuration"
 	"github.com/SENERGY-Platform/permission-search/lib/model"
 	"github.com/SENERGY-Platform/permission-search/lib/opensearchclient"
 	"github.com/SENERGY-Platform/permission-search/lib/rigthsproducer"
 	"github.com/opensearch-project/opensearch-go"
-	"github.com/opensearch-project/opensearch-go/opensearchutil"
-	"runtime/debug"
 )
 
 var DefaultBatchSize = 1000
 
-func ReplayPermissions(config configuration.Config, args []string) {
+// ReplayPermissions sends the rights of all resources of the topics in args (default all resources) to the rights topic
+// returns the first error; resources after the error are not replayed
+func ReplayPermissions(config configuration.Config, args []string) error {
 	dryrun := false
 	if len(args) == 0 || args[0] != "do" {
 		fmt.Println("Dry-Run; to execute use 'do' as the first argument (./permission-search replay-permissions do)")
@@ -45,9 +44,7 @@ func ReplayPermissions(config configuration.Config, args []string) {
 	}
 	client, err := opensearchclient.New(config)
 	if err != nil {
-		fmt.Println("ERROR:", err)
-		debug.PrintStack()
-		return
+		return err
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -57,79 +54,55 @@ func ReplayPermissions(config configuration.Config, args []string) {
 		producer, err = rigthsproducer.New(ctx, config)
 	}
 	if err != nil {
-		fmt.Println("ERROR:", err)
-		debug.PrintStack()
-		return
+		return err
 	}
 	for _, topic := range topics {
-		ReplayPermissionsOfResourceKind(producer, client, topic, DefaultBatchSize)
+		err = ReplayPermissionsOfResourceKind(ctx, producer, client, topic, DefaultBatchSize)
+		if err != nil {
+			return fmt.Errorf("replay of %v failed: %w", topic, err)
+		}
 	}
+	return nil
 }
 
-func ReplayPermissionsOfResourceKind(producer *rigthsproducer.Producer, client *opensearch.Client, kind string, batchSize int) {
-	for entry := range GetEntries(client, kind, batchSize) {
+func ReplayPermissionsOfResourceKind(ctx context.Context, producer *rigthsproducer.Producer, client *opensearch.Client, kind string, batchSize int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() //stops GetEntries if the producer fails
+	entries, errs := GetEntries(ctx, client, kind, batchSize)
+	for entry := range entries {
 		rights := entry.ToResourceRights().ResourceRightsBase
 		fmt.Printf("%#v %#v %#v\n", kind, entry.Resource, rights)
 		if producer != nil {
 			err, _ := producer.SetResourceRights(kind, entry.Resource, rights, "")
 			if err != nil {
-				fmt.Println("ERROR:", err)
-				debug.PrintStack()
-				return
+				return err
 			}
 		}
 	}
+	return <-errs
 }
 
-func GetEntries(client *opensearch.Client, kind string, batchSize int) (entries chan model.Entry) {
-	lastId := ""
+// GetEntries iterates over a point in time snapshot of kind, so that concurrent writes do not skip or duplicate entries
+// errs receives exactly one value (nil on success) after entries is closed; an error means that entries is incomplete
+func GetEntries(ctx context.Context, client *opensearch.Client, kind string, batchSize int) (entries chan model.Entry, errs chan error) {
 	entries = make(chan model.Entry, batchSize)
+	errs = make(chan error, 1)
 	go func() {
-		defer close(entries)
-		for {
-			query := map[string]interface{}{
-				"query": map[string]interface{}{
-					"match_all": map[string]interface{}{},
-				},
-			}
-			if lastId != "" {
-				query["search_after"] = []interface{}{lastId}
-			}
-			resp, err := client.Search(
-				client.Search.WithIndex(kind),
-				client.Search.WithVersion(true),
-				client.Search.WithSize(batchSize),
-				client.Search.WithSort("resource:asc"),
-				client.Search.WithBody(opensearchutil.NewJSONReader(query)),
-			)
-			if err != nil {
-				fmt.Println("ERROR:", err)
-				debug.PrintStack()
-				return
-			}
-			defer resp.Body.Close()
-			if resp.IsError() {
-				fmt.Println("ERROR:", resp.String())
-				debug.PrintStack()
-				return
-			}
-			pl := model.SearchResult[model.Entry]{}
-			err = json.NewDecoder(resp.Body).Decode(&pl)
-			if err != nil {
-				fmt.Println("ERROR:", err)
-				debug.PrintStack()
-				return
-			}
-
-			for _, hit := range pl.Hits.Hits {
-				entry := hit.Source
-				entries <- entry
-				lastId = entry.Resource
-			}
-			if len(pl.Hits.Hits) < batchSize {
-				return
-			}
+		query := map[string]interface{}{
+			"match_all": map[string]interface{}{},
 		}
+		err := opensearchclient.IterateEntries(ctx, client, kind, query, batchSize, func(batch []model.Entry) error {
+			for _, entry := range batch {
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		close(entries)
+		errs <- err
 	}()
-	return entries
+	return entries, errs
 }
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRightsCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "kitchen lamp"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	check := func(token string, id string, expected error) func(t *testing.T) {
+		return func(t *testing.T) {
+			err := c.CheckUserOrGroup(token, resource, id, "r")
+			if !errors.Is(err, expected) {
+				t.Error(err, expected)
+			}
+		}
+	}
+
+	t.Run("owner miss", check(testtoken, "d1", nil))
+	t.Run("owner hit", check(testtoken, "d1", nil))
+	t.Run("second owner miss", check(secondOwnerToken, "d1", model.ErrAccessDenied))
+	t.Run("second owner hit", check(secondOwnerToken, "d1", model.ErrAccessDenied))
+	t.Run("unknown miss", check(testtoken, "unknown", model.ErrAccessDenied))
+	t.Run("unknown hit", check(testtoken, "unknown", model.ErrAccessDenied))
+
+	t.Run("share d1", func(t *testing.T) {
+		err := w.SetUserRight(resource, "d1", secendOwnerTokenUser, "r")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("second owner after share", check(secondOwnerToken, "d1", nil))
+
+	t.Run("create unknown", saveTestDevice(w, resource, "unknown", map[string]interface{}{"name": "garage lamp"}))
+	t.Run("unknown after create", check(testtoken, "unknown", nil))
+
+	t.Run("delete d1", func(t *testing.T) {
+		err := w.DeleteFeatures(resource, model.CommandWrapper{Command: "DELETE", Id: "d1"})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("owner after delete", check(testtoken, "d1", model.ErrAccessDenied))
+
+	t.Run("stats", func(t *testing.T) {
+		stats, err := c.GetRightsCacheStats(admintoken)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !stats.Enabled || stats.Hits < 3 || stats.Misses < 6 || stats.Invalidations < 3 || stats.HitRate <= 0 {
+			t.Errorf("%#v", stats)
+		}
+	})
+
+	t.Run("stats without admin", func(t *testing.T) {
+		_, err := c.GetRightsCacheStats(testtoken)
+		if !errors.Is(err, model.ErrAccessDenied) {
+			t.Error(err)
+		}
+	})
+}
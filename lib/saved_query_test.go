@@ -0,0 +1,132 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSavedQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "d1", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "d2", "device_type_id": "dt2"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "d3", "device_type_id": "dt1"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	getIds := func(result interface{}) (ids []string, err error) {
+		list, err := client.JsonCast[[]map[string]interface{}](result)
+		if err != nil {
+			return ids, err
+		}
+		ids = []string{}
+		for _, element := range list {
+			ids = append(ids, element["id"].(string))
+		}
+		return ids, nil
+	}
+
+	t.Run("by_device_type", func(t *testing.T) {
+		result, _, err := c.SavedQuery(testtoken, resource, "by_device_type", client.SavedQueryRequest{Params: map[string]interface{}{"device_type_id": "dt1"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ids, err := getIds(result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(ids, []string{"d1", "d3"}) {
+			t.Error(ids)
+		}
+	})
+
+	t.Run("by_device_type paging", func(t *testing.T) {
+		result, _, err := c.SavedQuery(testtoken, resource, "by_device_type", client.SavedQueryRequest{
+			Params:    map[string]interface{}{"device_type_id": "dt1"},
+			Limit:     1,
+			Offset:    1,
+			WithTotal: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		withTotal, err := client.JsonCast[client.WithTotal[[]map[string]interface{}]](result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if withTotal.Total != 2 || len(withTotal.Result) != 1 || withTotal.Result[0]["id"] != "d3" {
+			t.Error(withTotal)
+		}
+	})
+
+	t.Run("by_device_type without rights", func(t *testing.T) {
+		result, _, err := c.SavedQuery(secondOwnerToken, resource, "by_device_type", client.SavedQueryRequest{Params: map[string]interface{}{"device_type_id": "dt1"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ids, err := getIds(result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(ids) != 0 {
+			t.Error(ids)
+		}
+	})
+
+	t.Run("missing param", func(t *testing.T) {
+		_, code, err := c.SavedQuery(testtoken, resource, "by_device_type", client.SavedQueryRequest{})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(code, err)
+		}
+	})
+
+	t.Run("unknown saved query", func(t *testing.T) {
+		_, code, err := c.SavedQuery(testtoken, resource, "unknown", client.SavedQueryRequest{})
+		if err == nil || code != http.StatusNotFound {
+			t.Error(code, err)
+		}
+	})
+}
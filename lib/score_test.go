@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScoreSort(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-types"
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "lamp", "description": "foo"}))
+	t.Run("create dt2", saveTestDeviceType(w, "dt2", map[string]interface{}{"name": "bar", "description": "lamp"}))
+	t.Run("create dt3", saveTestDeviceType(w, "dt3", map[string]interface{}{"name": "batz", "description": "sensor"}))
+
+	time.Sleep(2 * time.Second)
+
+	setBoosts := func(boosts map[string]float64) {
+		resourceConfig := config.Resources[resource]
+		resourceConfig.SearchBoosts = boosts
+		config.Resources[resource] = resourceConfig
+	}
+
+	list := func(queryCommons model.QueryListCommons, fields ...string) (ids []string, nextCursor string, err error) {
+		queryCommons.Rights = "r"
+		if queryCommons.Limit == 0 {
+			queryCommons.Limit = 10
+		}
+		result, err := q.ListWithTotal(testtoken, resource, model.ListOptions{
+			QueryListCommons: queryCommons,
+			TextSearch:       "lamp",
+			SearchFields:     fields,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		ids = []string{}
+		for _, element := range result.Result.([]map[string]interface{}) {
+			ids = append(ids, element["id"].(string))
+		}
+		return ids, result.NextCursor, nil
+	}
+
+	check := func(queryCommons model.QueryListCommons, expectedIds []string, fields ...string) func(t *testing.T) {
+		return func(t *testing.T) {
+			ids, _, err := list(queryCommons, fields...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(ids, expectedIds) {
+				t.Error(ids, expectedIds)
+			}
+		}
+	}
+
+	setBoosts(map[string]float64{"name": 10})
+	t.Run("name boost", check(model.QueryListCommons{}, []string{"dt1", "dt2"}))
+
+	setBoosts(map[string]float64{"features.description": 10})
+	t.Run("description boost", check(model.QueryListCommons{}, []string{"dt2", "dt1"}))
+	t.Run("explicit score sort", check(model.QueryListCommons{SortBy: model.SortByScore}, []string{"dt2", "dt1"}))
+	t.Run("explicit id sort", check(model.QueryListCommons{SortBy: "id"}, []string{"dt1", "dt2"}))
+	t.Run("field boost overrides config", check(model.QueryListCommons{}, []string{"dt1", "dt2"}, "name^100", "description"))
+
+	t.Run("cursor", func(t *testing.T) {
+		queryCommons := model.QueryListCommons{Limit: 1, WithCursor: true}
+		ids := []string{}
+		for i := 0; i < 5; i++ {
+			page, next, err := list(queryCommons)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids = append(ids, page...)
+			if next == "" {
+				break
+			}
+			queryCommons.After = &model.ListAfter{Cursor: next}
+		}
+		if !reflect.DeepEqual(ids, []string{"dt2", "dt1"}) {
+			t.Error(ids)
+		}
+	})
+}
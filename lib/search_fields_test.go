@@ -0,0 +1,132 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-types"
+
+	t.Run("create dt1", saveTestDeviceType(w, "dt1", map[string]interface{}{"name": "kitchen sensor", "description": "bar"}))
+	t.Run("create dt2", saveTestDeviceType(w, "dt2", map[string]interface{}{"name": "foo", "description": "mounted in the kitchen"}))
+	t.Run("create dt3", saveTestDeviceType(w, "dt3", map[string]interface{}{"name": "batz", "description": "outdoor"}))
+
+	time.Sleep(2 * time.Second)
+
+	options := func(search string, mode model.SearchMode, fields ...string) model.ListOptions {
+		return model.ListOptions{
+			QueryListCommons: model.QueryListCommons{Limit: 10, Rights: "r", SortBy: "id"},
+			TextSearch:       search,
+			SearchMode:       mode,
+			SearchFields:     fields,
+		}
+	}
+
+	check := func(options model.ListOptions, expectedIds []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := q.List(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids := []string{}
+			for _, element := range result {
+				ids = append(ids, element["id"].(string))
+			}
+			if !reflect.DeepEqual(ids, expectedIds) {
+				t.Error(ids, expectedIds)
+			}
+			total, err := q.Total(testtoken, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if total != int64(len(expectedIds)) {
+				t.Error(total, len(expectedIds))
+			}
+		}
+	}
+
+	t.Run("all fields", check(options("kitchen", ""), []string{"dt1", "dt2"}))
+	t.Run("name", check(options("kitchen", "", "name"), []string{"dt1"}))
+	t.Run("description", check(options("kitch", "", "features.description"), []string{"dt2"}))
+	t.Run("boost", check(options("kitchen", "", "name^3", "description"), []string{"dt1", "dt2"}))
+	t.Run("fuzzy name", check(options("kitchn", model.SearchModeFuzzy, "name"), []string{"dt1"}))
+	t.Run("exact name", check(options("KITCHEN SENSOR", model.SearchModeExact, "name"), []string{"dt1"}))
+	t.Run("exact description", check(options("kitchen sensor", model.SearchModeExact, "description"), []string{}))
+
+	checkBadRequest := func(options model.ListOptions) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, err := q.List(testtoken, resource, options)
+			if !errors.Is(err, model.ErrBadRequest) {
+				t.Error(err)
+			}
+		}
+	}
+
+	t.Run("field without copy_to", checkBadRequest(options("kitchen", "", "service")))
+	t.Run("unknown field", checkBadRequest(options("kitchen", "", "unknown")))
+	t.Run("invalid boost", checkBadRequest(options("kitchen", "", "name^x")))
+	t.Run("fields without search", checkBadRequest(options("", "", "name")))
+
+	t.Run("find", func(t *testing.T) {
+		result, _, err := q.Query(testtoken, model.QueryMessage{
+			Resource: resource,
+			Find: &model.QueryFind{
+				QueryListCommons: model.QueryListCommons{SortBy: "id"},
+				Search:           "kitchen",
+				SearchFields:     []string{"description"},
+				Highlight:        true,
+			},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list := result.([]map[string]interface{})
+		if len(list) != 1 || list[0]["id"] != "dt2" {
+			t.Error(list)
+			return
+		}
+		expected := map[string][]string{"description": {"mounted in the <em>kitchen</em>"}}
+		if !reflect.DeepEqual(list[0]["highlight"], expected) {
+			t.Errorf("%#v", list[0]["highlight"])
+		}
+	})
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSearchMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "kitchen lamp"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "lamp"}))
+	t.Run("create dg3", saveTestDeviceGroup(w, resource, "dg3", map[string]interface{}{"name": "living room light"}))
+
+	time.Sleep(2 * time.Second)
+
+	options := func(search string, mode model.SearchMode) model.ListOptions {
+		return model.ListOptions{
+			QueryListCommons: model.QueryListCommons{Limit: 10, Rights: "r", SortBy: "name"},
+			TextSearch:       search,
+			SearchMode:       mode,
+		}
+	}
+
+	check := func(search string, mode model.SearchMode, expectedNames []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := q.List(testtoken, resource, options(search, mode))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			names := []string{}
+			for _, element := range result {
+				names = append(names, element["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Error(names, expectedNames)
+			}
+			total, err := q.Total(testtoken, resource, options(search, mode))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if total != int64(len(expectedNames)) {
+				t.Error(total, len(expectedNames))
+			}
+		}
+	}
+
+	t.Run("default typo", check("lmap", "", []string{}))
+	t.Run("fuzzy typo", check("lmap", model.SearchModeFuzzy, []string{"kitchen lamp", "lamp"}))
+	t.Run("default", check("lamp", model.SearchModeDefault, []string{"kitchen lamp", "lamp"}))
+	t.Run("exact", check("LAMP", model.SearchModeExact, []string{"lamp"}))
+	t.Run("prefix", check("kitchen l", model.SearchModePrefix, []string{"kitchen lamp"}))
+	t.Run("prefix not in the middle", check("lam", model.SearchModePrefix, []string{"lamp"}))
+	t.Run("phrase", check("room light", model.SearchModePhrase, []string{"living room light"}))
+	t.Run("phrase order", check("light room", model.SearchModePhrase, []string{}))
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := q.List(testtoken, resource, options("lamp", "foo"))
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("mode without search", func(t *testing.T) {
+		_, err := q.List(testtoken, resource, options("", model.SearchModeFuzzy))
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+
+	resourceConfig := config.Resources[resource]
+	resourceConfig.SearchMode = model.SearchModeExact
+	config.Resources[resource] = resourceConfig
+
+	t.Run("configured default mode", check("lamp", "", []string{"lamp"}))
+	t.Run("request overrides configured mode", check("lamp", model.SearchModeDefault, []string{"kitchen lamp", "lamp"}))
+}
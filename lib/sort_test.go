@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMultiFieldSort(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, q, w, err := getTestEnv(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "device-groups"
+
+	t.Run("create dg1", saveTestDeviceGroup(w, resource, "dg1", map[string]interface{}{"name": "a", "criteria_short": "x"}))
+	t.Run("create dg2", saveTestDeviceGroup(w, resource, "dg2", map[string]interface{}{"name": "b", "criteria_short": "y"}))
+	t.Run("create dg3", saveTestDeviceGroup(w, resource, "dg3", map[string]interface{}{"name": "c", "criteria_short": "x"}))
+	t.Run("create dg4", saveTestDeviceGroup(w, resource, "dg4", map[string]interface{}{"name": "d"}))
+
+	time.Sleep(2 * time.Second)
+
+	check := func(queryCommons model.QueryListCommons, expectedNames []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			queryCommons.Limit = 100
+			queryCommons.Rights = "r"
+			result, err := q.GetList(createTestToken("testOwner", []string{"user"}), resource, queryCommons)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			names := []string{}
+			for _, element := range result {
+				names = append(names, element["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Error(names, expectedNames)
+			}
+		}
+	}
+
+	t.Run("sort list", check(model.QueryListCommons{Sort: []model.SortField{
+		{Field: "criteria_short", Desc: true},
+		{Field: "name", Desc: true},
+	}}, []string{"b", "c", "a", "d"}))
+
+	t.Run("sort list missing last", check(model.QueryListCommons{Sort: []model.SortField{
+		{Field: "criteria_short", Desc: true, Missing: model.SortMissingLast},
+		{Field: "name"},
+	}}, []string{"b", "a", "c", "d"}))
+
+	t.Run("sort url param", func(t *testing.T) {
+		queryCommons, err := model.GetQueryListCommonsFromUrlQuery(url.Values{"sort": {"criteria_short.missing_first,name.desc"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		check(queryCommons, []string{"d", "c", "a", "b"})(t)
+	})
+
+	t.Run("sort url param roundtrip", func(t *testing.T) {
+		expected := model.QueryListCommons{Sort: []model.SortField{
+			{Field: "annotations.connected", Desc: true},
+			{Field: "name", Missing: model.SortMissingFirst},
+		}}
+		actual, err := model.GetQueryListCommonsFromUrlQuery(expected.QueryValues())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(actual.Sort, expected.Sort) {
+			t.Error(actual.Sort, expected.Sort)
+		}
+	})
+
+	t.Run("invalid missing", func(t *testing.T) {
+		err := model.QueryListCommons{Sort: []model.SortField{{Field: "name", Missing: "middle"}}}.Validate()
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
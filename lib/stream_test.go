@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "kitchen lamp", "device_type_id": "dt1"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "kitchen sensor", "device_type_id": "dt2"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "living lamp", "device_type_id": "dt1"}))
+	t.Run("create d4", saveTestDevice(w, resource, "d4", map[string]interface{}{"name": "garage lamp", "device_type_id": "dt2"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	check := func(token string, options client.ListOptions, expectedIds []string) func(t *testing.T) {
+		return func(t *testing.T) {
+			it := client.Stream[map[string]interface{}](c, token, resource, options)
+			defer it.Close()
+			ids := []string{}
+			for it.Next() {
+				ids = append(ids, it.Value()["id"].(string))
+			}
+			if it.Err() != nil {
+				t.Error(it.Err())
+				return
+			}
+			if !reflect.DeepEqual(ids, expectedIds) {
+				t.Error(ids, expectedIds)
+			}
+		}
+	}
+
+	t.Run("all", check(testtoken, client.ListOptions{}, []string{"d1", "d2", "d3", "d4"}))
+	t.Run("limit is ignored", check(testtoken, client.ListOptions{QueryListCommons: model.QueryListCommons{Limit: 1, Offset: 1}}, []string{"d1", "d2", "d3", "d4"}))
+	t.Run("filter", check(testtoken, client.ListOptions{Filter: &model.Selection{Condition: model.ConditionConfig{
+		Feature:   "features.device_type_id",
+		Operation: model.QueryEqualOperation,
+		Value:     "dt1",
+	}}}, []string{"d1", "d3"}))
+	t.Run("expression", check(testtoken, client.ListOptions{Expression: "name prefix 'kitchen'"}, []string{"d1", "d2"}))
+	t.Run("search", check(testtoken, client.ListOptions{TextSearch: "lamp"}, []string{"d1", "d3", "d4"}))
+	t.Run("ids", check(testtoken, client.ListOptions{ListIds: []string{"d4", "d2", "unknown"}}, []string{"d2", "d4"}))
+	t.Run("rights", check(secondOwnerToken, client.ListOptions{}, []string{}))
+
+	t.Run("equals list", func(t *testing.T) {
+		list, err := c.List(testtoken, resource, client.ListOptions{QueryListCommons: model.QueryListCommons{Limit: 100, SortBy: "id"}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		streamed := []map[string]interface{}{}
+		it := client.Stream[map[string]interface{}](c, testtoken, resource, client.ListOptions{})
+		defer it.Close()
+		for it.Next() {
+			streamed = append(streamed, it.Value())
+		}
+		if it.Err() != nil {
+			t.Error(it.Err())
+			return
+		}
+		if !reflect.DeepEqual(streamed, list) {
+			t.Errorf("\n%#v\n%#v\n", streamed, list)
+		}
+	})
+
+	t.Run("ndjson", testRequestWithToken(config, testtoken, "GET", "/v3/stream/"+resource+"?ids=d1", nil, http.StatusOK, nil))
+	t.Run("highlight rejected", testRequestWithToken(config, testtoken, "GET", "/v3/stream/"+resource+"?search=lamp&highlight=true", nil, http.StatusBadRequest, nil))
+	t.Run("did you mean rejected", func(t *testing.T) {
+		it := client.Stream[map[string]interface{}](c, testtoken, resource, client.ListOptions{TextSearch: "lamb", DidYouMean: true})
+		defer it.Close()
+		if it.Next() {
+			t.Error("expected no element")
+		}
+		if it.Err() == nil {
+			t.Error("expected error")
+		}
+	})
+}
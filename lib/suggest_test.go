@@ -0,0 +1,102 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/client"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSuggest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("short")
+	}
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, _, w, err := getTestEnvWithApi(ctx, wg, t)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	resource := "devices"
+
+	t.Run("create d1", saveTestDevice(w, resource, "d1", map[string]interface{}{"name": "Kitchen Lamp"}))
+	t.Run("create d2", saveTestDevice(w, resource, "d2", map[string]interface{}{"name": "Kitchen Sensor"}))
+	t.Run("create d3", saveTestDevice(w, resource, "d3", map[string]interface{}{"name": "Living Room Lamp"}))
+
+	time.Sleep(2 * time.Second)
+
+	c := client.NewClient("http://localhost:" + config.ServerPort)
+
+	check := func(token string, options client.SuggestOptions, expected []client.Suggestion) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := c.Suggest(token, resource, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Error(result, expected)
+			}
+		}
+	}
+
+	t.Run("prefix", check(testtoken, client.SuggestOptions{Query: "kit"}, []client.Suggestion{
+		{Id: "d1", Text: "Kitchen Lamp"},
+		{Id: "d2", Text: "Kitchen Sensor"},
+	}))
+	t.Run("multiple words", check(testtoken, client.SuggestOptions{Query: "kitchen la"}, []client.Suggestion{
+		{Id: "d1", Text: "Kitchen Lamp"},
+	}))
+	t.Run("second word", check(testtoken, client.SuggestOptions{Query: "lam"}, []client.Suggestion{
+		{Id: "d1", Text: "Kitchen Lamp"},
+		{Id: "d3", Text: "Living Room Lamp"},
+	}))
+	t.Run("limit", check(testtoken, client.SuggestOptions{Query: "kit", Limit: 1, Field: "name"}, []client.Suggestion{
+		{Id: "d1", Text: "Kitchen Lamp"},
+	}))
+	t.Run("no rights", check(secondOwnerToken, client.SuggestOptions{Query: "kit"}, []client.Suggestion{}))
+
+	t.Run("missing query", func(t *testing.T) {
+		_, err := c.Suggest(testtoken, resource, client.SuggestOptions{})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+	t.Run("field without suggest", func(t *testing.T) {
+		_, err := c.Suggest(testtoken, resource, client.SuggestOptions{Query: "kit", Field: "local_id"})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+	t.Run("resource without suggest", func(t *testing.T) {
+		_, err := c.Suggest(testtoken, "aspects", client.SuggestOptions{Query: "kit"})
+		if !errors.Is(err, model.ErrBadRequest) {
+			t.Error(err)
+		}
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/SENERGY-Platform/permission-search/lib/model"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -93,4 +94,36 @@ func TestTotal(t *testing.T) {
 	t.Run("check all", check("", 12))
 	t.Run("check search", check("search=firstsearch", 5))
 	t.Run("check filter", check("filter=device_type_id:dt2", 7))
+
+	t.Run("list with_total", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "http://localhost:"+config.ServerPort+"/v3/resources/devices?limit=3&filter=device_type_id:dt2&with_total=true", nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header.Set("Authorization", testtoken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 {
+			temp, _ := ioutil.ReadAll(resp.Body)
+			t.Error(resp.StatusCode, string(temp))
+			return
+		}
+		result := model.WithTotal{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Total != 7 {
+			t.Error(result.Total)
+		}
+		if list, ok := result.Result.([]interface{}); !ok || len(list) != 3 {
+			t.Errorf("%#v", result.Result)
+		}
+	})
 }
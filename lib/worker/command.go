@@ -29,6 +29,7 @@ import (
 )
 
 func (this *Worker) SetUserRight(kind string, resource string, user string, rights string) (err error) {
+	defer this.query.InvalidateRightsCache(kind, resource)
 	ctx := context.Background()
 	exists, err := this.query.ResourceExists(kind, resource)
 	if err != nil {
@@ -69,6 +70,7 @@ func (this *Worker) SetUserRight(kind string, resource string, user string, righ
 }
 
 func (this *Worker) SetGroupRight(kind string, resource string, group string, rights string) (err error) {
+	defer this.query.InvalidateRightsCache(kind, resource)
 	ctx := context.Background()
 	exists, err := this.query.ResourceExists(kind, resource)
 	if err != nil {
@@ -110,6 +112,7 @@ func (this *Worker) SetGroupRight(kind string, resource string, group string, ri
 }
 
 func (this *Worker) DeleteUserRight(kind string, resource string, user string) (err error) {
+	defer this.query.InvalidateRightsCache(kind, resource)
 	ctx := context.Background()
 	exists, err := this.query.ResourceExists(kind, resource)
 	if err != nil {
@@ -149,6 +152,7 @@ func (this *Worker) DeleteUserRight(kind string, resource string, user string) (
 }
 
 func (this *Worker) DeleteGroupRight(kind string, resource string, group string) (err error) {
+	defer this.query.InvalidateRightsCache(kind, resource)
 	ctx := context.Background()
 	exists, err := this.query.ResourceExists(kind, resource)
 	if err != nil {
@@ -189,6 +193,7 @@ func (this *Worker) DeleteGroupRight(kind string, resource string, group string)
 }
 
 func (this *Worker) UpdateFeatures(kind string, msg []byte, command model.CommandWrapper) (err error) {
+	defer this.query.InvalidateRightsCache(kind, command.Id)
 	ctx := this.getTimeout()
 	features, err := this.MsgToFeatures(kind, msg)
 	if err != nil {
@@ -261,6 +266,7 @@ func (this *Worker) UpdateFeatures(kind string, msg []byte, command model.Comman
 }
 
 func (this *Worker) UpdateRights(kind string, msg []byte, command model.CommandWrapper) (err error) {
+	defer this.query.InvalidateRightsCache(kind, command.Id)
 	ctx := this.getTimeout()
 	rights, err := this.MsgToRights(msg)
 	if err != nil {
@@ -323,6 +329,7 @@ func (this *Worker) UpdateRights(kind string, msg []byte, command model.CommandW
 }
 
 func (this *Worker) DeleteFeatures(kind string, command model.CommandWrapper) (err error) {
+	defer this.query.InvalidateRightsCache(kind, command.Id)
 	ctx := context.Background()
 	exists, err := this.query.ResourceExists(kind, command.Id)
 	if err != nil {
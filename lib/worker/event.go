@@ -104,6 +104,28 @@ func InitEventHandling(ctx context.Context, config configuration.Config, worker
 	return
 }
 
+// InitRightsCacheInvalidation calls invalidate for every resource in the done_topic, to invalidate changes of workers in other instances
+// does nothing if the rights cache is disabled or no kafka is configured
+func InitRightsCacheInvalidation(ctx context.Context, config configuration.Config, invalidate func(kind string, resource string)) error {
+	if config.RightsCacheSize <= 0 || config.KafkaUrl == "" || config.KafkaUrl == "-" || config.DoneTopic == "" {
+		return nil
+	}
+	return kafka.NewLatestMessagesConsumer(ctx, config.KafkaUrl, config.DoneTopic, func(msg []byte) error {
+		done := model.Done{}
+		err := json.Unmarshal(msg, &done)
+		if err != nil {
+			log.Println("WARNING: unable to parse done message --> ignore", err, string(msg))
+			return nil
+		}
+		if done.ResourceKind != "" && done.ResourceId != "" {
+			invalidate(done.ResourceKind, done.ResourceId)
+		}
+		return nil
+	}, func(err error) {
+		config.HandleFatalError(err)
+	})
+}
+
 func replacePlaceholders(list []string, replace map[string][]string) (result []string) {
 	for _, element := range list {
 		if repl, ok := replace[element]; ok {
@@ -25,4 +25,5 @@ type Query interface {
 	GetClient() *opensearch.Client
 	GetResourceEntry(kind string, resource string) (result model.Entry, version model.ResourceVersion, err error)
 	ResourceExists(kind string, resource string) (exists bool, err error)
+	InvalidateRightsCache(kind string, resource string)
 }
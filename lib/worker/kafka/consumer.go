@@ -81,6 +81,62 @@ func NewConsumer(ctx context.Context, broker string, groupId string, topic strin
 	return nil
 }
 
+// NewLatestMessagesConsumer reads all partitions of topic without consumer group, starting after the latest message
+// every instance receives every new message and no consumer group offsets are left on the broker
+// partitions added after the start are not consumed
+func NewLatestMessagesConsumer(ctx context.Context, broker string, topic string, listener func(delivery []byte) error, errhandler func(err error)) error {
+	err := InitTopic(broker, topic)
+	if err != nil {
+		log.Println("ERROR: unable to create topic", err)
+		return err
+	}
+	conn, err := kafka.Dial("tcp", broker)
+	if err != nil {
+		return err
+	}
+	partitions, err := conn.ReadPartitions(topic)
+	conn.Close()
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     []string{broker},
+			Topic:       topic,
+			Partition:   partition.ID,
+			MaxWait:     1 * time.Second,
+			Logger:      log.New(io.Discard, "", 0),
+			ErrorLogger: log.New(os.Stdout, "[KAFKA-ERROR] ", log.Default().Flags()),
+		})
+		err = r.SetOffset(kafka.LastOffset)
+		if err != nil {
+			r.Close()
+			return err
+		}
+		go func() {
+			defer r.Close()
+			defer log.Println("close consumer for topic ", topic, r.Config().Partition)
+			for {
+				m, err := r.ReadMessage(ctx)
+				if err == io.EOF || errors.Is(err, context.Canceled) {
+					return
+				}
+				if err != nil {
+					log.Println("ERROR: while consuming topic ", topic, err)
+					errhandler(err)
+					return
+				}
+				err = listener(m.Value)
+				if err != nil {
+					log.Println("ERROR: unable to handle message", err)
+					errhandler(err)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
 func NewConsumerWithMultipleTopics(ctx context.Context, broker string, groupId string, topics []string, debug bool, listener func(topic string, delivery []byte) error, errhandler func(topice string, err error)) error {
 	if len(topics) == 0 {
 		return nil
@@ -81,8 +81,7 @@ func HandleCli(config configuration.Config, args []string) error {
 	}
 	switch args[0] {
 	case "replay-permissions":
-		replay.ReplayPermissions(config, args[1:])
-		return nil
+		return replay.ReplayPermissions(config, args[1:])
 	case "update-indexes":
 		resources := args[1:]
 		if len(resources) == 0 {